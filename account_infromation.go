@@ -1,10 +1,8 @@
 package hunter
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"net/http"
 )
 
 // AccountInformation is returned by the Account function.
@@ -15,13 +13,41 @@ type AccountInformation struct {
 		Email     string `json:"email"`
 		PlanName  string `json:"plan_name"`
 		PlanLevel int    `json:"plan_level"`
-		ResetDate string `json:"reset_date"`
+		ResetDate Date   `json:"reset_date"`
 		TeamID    int    `json:"team_id"`
 		Calls     struct {
 			Used      int `json:"used"`
 			Available int `json:"available"`
 		} `json:"calls"`
 	} `json:"data"`
+	// Raw is the untouched response body the result was decoded from.
+	Raw json.RawMessage `json:"-"`
+	// Response describes the HTTP response the result was decoded from.
+	Response *ResponseMeta `json:"-"`
+}
+
+// credits returns zero, since account information is free.
+func (r *AccountInformation) credits() float64 {
+	return 0
+}
+
+func (r *AccountInformation) setResponse(body []byte, meta *ResponseMeta) {
+	r.Raw = body
+	r.Response = meta
+}
+
+func (r *AccountInformation) response() ([]byte, *ResponseMeta) {
+	return r.Raw, r.Response
+}
+
+// DaysUntilReset returns the number of days until the account's
+// calls are reset, which is zero on the reset date.
+func (a *AccountInformation) DaysUntilReset() int {
+	days := daysBetween(now(), a.Data.ResetDate.Time)
+	if days < 0 {
+		return 0
+	}
+	return days
 }
 
 // Account is a function to get information regarding your
@@ -30,16 +56,7 @@ type AccountInformation struct {
 // This context-based version of the function would be more suitable for long-running
 // applications like servers.
 func (c *Client) Account() (*AccountInformation, error) {
-	body, err := c.request(context.Background(), http.MethodGet, "https://api.hunter.io/v2/account", nil)
-	if err != nil {
-		return nil, err
-	}
-	result := new(AccountInformation)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
+	return c.AccountWithContext(context.Background())
 }
 
 // AccountWithContext is a function to get information regarding your
@@ -48,13 +65,8 @@ func (c *Client) Account() (*AccountInformation, error) {
 // This context-based version of the function would be more suitable for long-running
 // applications like servers.
 func (c *Client) AccountWithContext(ctx context.Context) (*AccountInformation, error) {
-	body, err := c.request(ctx, http.MethodGet, "https://api.hunter.io/v2/account", nil)
-	if err != nil {
-		return nil, err
-	}
 	result := new(AccountInformation)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
+	if err := c.call(ctx, "account", nil, result); err != nil {
 		return nil, err
 	}
 	return result, nil
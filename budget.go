@@ -0,0 +1,321 @@
+package hunter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned, wrapped with more details, when a call
+// would spend more credits than a Client's Budget allows. No request is
+// sent to the API in that case.
+var ErrBudgetExceeded = errors.New("the credit budget has been exceeded")
+
+// AllEndpoints is the Budget.MaxCredits key limiting the total
+// credits spent across every endpoint.
+const AllEndpoints = "*"
+
+// endpointCredits is the most credits a call to each endpoint may use.
+// Endpoints which aren't listed are free.
+var endpointCredits = map[string]float64{
+	"domain-search":  1,
+	"email-finder":   1,
+	"email-verifier": 0.5,
+}
+
+// EstimatedCredits returns the most credits a call to the given
+// endpoint, like "domain-search", may use.
+func EstimatedCredits(endpoint string) float64 {
+	return endpointCredits[endpoint]
+}
+
+// Spend is an amount of credits spent on an endpoint.
+type Spend struct {
+	Time     time.Time `json:"time"`
+	Endpoint string    `json:"endpoint"`
+	Credits  float64   `json:"credits"`
+	// Reservation identifies the most credits a call in progress could
+	// use, which are replaced by the credits it used once it's done.
+	Reservation string `json:"reservation,omitempty"`
+}
+
+// reservationTimeout is how long a reservation is held for, after which it
+// is dropped, in case the process which made it stopped before settling it.
+const reservationTimeout = 10 * time.Minute
+
+// Budget limits the credits a Client may spend. Before each call, the most
+// credits it could use is checked against the limits and reserved, and once
+// it's done the credits it actually used are recorded instead.
+type Budget struct {
+	// MaxCredits limits the credits spent on each endpoint, keyed by the
+	// endpoint name like "domain-search". Use the AllEndpoints key to limit
+	// the total. Endpoints without a limit are unlimited.
+	MaxCredits map[string]float64
+	// Period, if non-zero, only counts credits spent within the period
+	// before each call, like the last 24 hours. Otherwise all the recorded
+	// credits are counted.
+	Period time.Duration
+	// Path, if set, is a local file the spend is recorded in, so it is shared
+	// by every process using it, which lock it while they update it.
+	// Otherwise only this process' spend is counted.
+	Path string
+
+	mu     sync.Mutex
+	spends []Spend
+}
+
+// NewBudget returns a Budget limiting the total credits spent by this process.
+func NewBudget(maxCredits float64) *Budget {
+	return &Budget{MaxCredits: map[string]float64{AllEndpoints: maxCredits}}
+}
+
+// Spent returns the credits recorded for the endpoint within the budget's
+// period, or for every endpoint if given AllEndpoints.
+func (b *Budget) Spent(endpoint string) (float64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	spends, err := b.load()
+	if err != nil {
+		return 0, err
+	}
+	return b.total(spends, endpoint, false), nil
+}
+
+// Remaining returns the credits left for the endpoint, taking into account
+// both its own limit and the total limit, as well as the credits reserved
+// by calls in progress. It returns -1 if it is unlimited.
+func (b *Budget) Remaining(endpoint string) (float64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	spends, err := b.load()
+	if err != nil {
+		return 0, err
+	}
+	remaining := -1.0
+	for _, key := range []string{endpoint, AllEndpoints} {
+		limit, ok := b.MaxCredits[key]
+		if !ok {
+			continue
+		}
+		left := limit - b.total(spends, key, true)
+		if left < 0 {
+			left = 0
+		}
+		if remaining < 0 || left < remaining {
+			remaining = left
+		}
+	}
+	return remaining, nil
+}
+
+// reserve checks a call to the endpoint fits in the budget, holding its
+// estimated credits until settle is called with the returned reservation.
+// The reservation is empty for free endpoints.
+func (b *Budget) reserve(endpoint string) (string, error) {
+	estimate := EstimatedCredits(endpoint)
+	if estimate == 0 {
+		return "", nil
+	}
+	reservation, err := newReservation()
+	if err != nil {
+		return "", err
+	}
+	err = b.update(func(spends []Spend) ([]Spend, error) {
+		for _, key := range []string{endpoint, AllEndpoints} {
+			limit, ok := b.MaxCredits[key]
+			if !ok {
+				continue
+			}
+			spent := b.total(spends, key, true)
+			if spent+estimate > limit {
+				return nil, fmt.Errorf("%w: %s would use up to %g credits, but %g of the %g allowed for %s are already spent", ErrBudgetExceeded, endpoint, estimate, spent, limit, budgetKeyName(key))
+			}
+		}
+		return append(spends, Spend{Time: now().UTC(), Endpoint: endpoint, Credits: estimate, Reservation: reservation}), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return reservation, nil
+}
+
+// settle releases the reservation made for a call to the
+// endpoint, recording the credits it used instead.
+func (b *Budget) settle(reservation, endpoint string, credits float64) error {
+	if reservation == "" && credits == 0 {
+		return nil
+	}
+	return b.update(func(spends []Spend) ([]Spend, error) {
+		kept := spends[:0]
+		for _, spend := range spends {
+			if reservation == "" || spend.Reservation != reservation {
+				kept = append(kept, spend)
+			}
+		}
+		if credits > 0 {
+			kept = append(kept, Spend{Time: now().UTC(), Endpoint: endpoint, Credits: credits})
+		}
+		return kept, nil
+	})
+}
+
+// update replaces the spends with those returned by fn, unless it returns an
+// error. The spend record, if any, is locked so other processes wait.
+func (b *Budget) update(fn func([]Spend) ([]Spend, error)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Path != "" {
+		unlock, err := lockFile(b.Path + ".lock")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	spends, err := b.load()
+	if err != nil {
+		return err
+	}
+	spends, err = fn(spends)
+	if err != nil {
+		return err
+	}
+	return b.save(spends)
+}
+
+// total returns the credits spent on the endpoint, or every endpoint if
+// given AllEndpoints, within the budget's period, including the credits
+// reserved if asked to.
+func (b *Budget) total(spends []Spend, endpoint string, reserved bool) float64 {
+	var total float64
+	for _, spend := range spends {
+		if spend.Reservation != "" && !reserved {
+			continue
+		}
+		if endpoint == AllEndpoints || spend.Endpoint == endpoint {
+			total += spend.Credits
+		}
+	}
+	return total
+}
+
+// load returns the spends within the budget's period, and the
+// reservations which haven't timed out.
+func (b *Budget) load() ([]Spend, error) {
+	spends := b.spends
+	if b.Path != "" {
+		data, err := ioutil.ReadFile(b.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		spends = nil
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &spends); err != nil {
+				return nil, fmt.Errorf("hunter: invalid spend record %s: %w", b.Path, err)
+			}
+		}
+	}
+	var kept []Spend
+	for _, spend := range spends {
+		if spend.Reservation != "" && !spend.Time.After(now().Add(-reservationTimeout)) {
+			continue
+		}
+		if b.Period != 0 && !spend.Time.After(now().Add(-b.Period)) {
+			continue
+		}
+		kept = append(kept, spend)
+	}
+	return kept, nil
+}
+
+// save stores the spends, in the spend record if the budget has one,
+// which is replaced at once so it is never read half written.
+func (b *Budget) save(spends []Spend) error {
+	if b.Path == "" {
+		b.spends = spends
+		return nil
+	}
+	data, err := json.Marshal(spends)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(b.Path), 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(b.Path), ".spend-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), b.Path)
+}
+
+// lockTimeout is how long to wait for another process to unlock
+// the spend record, and staleLockAge is the age of a lock file
+// left behind by a process which stopped before removing it.
+const (
+	lockTimeout  = 10 * time.Second
+	staleLockAge = 30 * time.Second
+)
+
+// lockFile creates the lock file at the path, waiting while another process
+// holds it, and returns the function removing it.
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("hunter: timed out waiting for the lock %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// newReservation returns a random reservation identifier.
+func newReservation() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// DefaultSpendRecordPath returns the default path of the spend record
+// used by budgets, next to the default ledger.
+func DefaultSpendRecordPath() string {
+	return filepath.Join(dataDir(), "spend.json")
+}
+
+func budgetKeyName(key string) string {
+	if key == AllEndpoints {
+		return "all endpoints"
+	}
+	return key
+}
@@ -0,0 +1,171 @@
+package hunter
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Budget(t *testing.T) {
+	client := newTestClient(200, `{"data":{"emails":[{"value":"patrick@stripe.com"}]}}`)
+	client.Budget = NewBudget(2)
+	for i := 0; i < 2; i++ {
+		if _, err := client.DomainSearch(Params{"domain": "stripe.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := client.DomainSearch(Params{"domain": "stripe.com"})
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatal("expected ErrBudgetExceeded, got:", err)
+	}
+	if _, err := client.Account(); err != nil {
+		t.Fatal("expected free calls to be allowed, got:", err)
+	}
+	spent, err := client.Budget.Spent(AllEndpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spent != 2 {
+		t.Error("expected 2 credits spent, got:", spent)
+	}
+}
+
+func TestBudget_perEndpoint(t *testing.T) {
+	budget := &Budget{MaxCredits: map[string]float64{"email-verifier": 1}}
+	client := newTestClient(200, `{"data":{"email":"steli@close.io"}}`)
+	client.Budget = budget
+	for i := 0; i < 2; i++ {
+		if _, err := client.VerifyEmail(Params{"email": "steli@close.io"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.VerifyEmail(Params{"email": "steli@close.io"}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatal("expected ErrBudgetExceeded, got:", err)
+	}
+	remaining, err := budget.Remaining("domain-search")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining != -1 {
+		t.Error("expected an unlimited domain search budget, got:", remaining)
+	}
+}
+
+func TestBudget_unusedReservation(t *testing.T) {
+	client := newTestClient(200, `{"data":{"emails":[]}}`)
+	client.Budget = NewBudget(1)
+	for i := 0; i < 3; i++ {
+		if _, err := client.DomainSearch(Params{"domain": "example.com"}); err != nil {
+			t.Fatal("expected searches without results to be free, got:", err)
+		}
+	}
+}
+
+func TestBudget_periodRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spend.json")
+	fixNow(t, "2019-01-01")
+	first := &Budget{MaxCredits: map[string]float64{AllEndpoints: 1}, Period: 24 * time.Hour, Path: path}
+	reservation, err := first.reserve("domain-search")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := &Budget{MaxCredits: map[string]float64{AllEndpoints: 1}, Period: 24 * time.Hour, Path: path}
+	if _, err := second.reserve("email-finder"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatal("expected the reservation to be shared, got:", err)
+	}
+	if err := first.settle(reservation, "domain-search", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.reserve("email-finder"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatal("expected the spend record to be shared, got:", err)
+	}
+	if spent, err := second.Spent(AllEndpoints); err != nil || spent != 1 {
+		t.Errorf("expected 1 credit spent, got %g and %v", spent, err)
+	}
+	fixNow(t, "2019-01-03")
+	if _, err := second.reserve("email-finder"); err != nil {
+		t.Fatal("expected the spend to expire after the period, got:", err)
+	}
+}
+
+func TestBudget_concurrentProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spend.json")
+	// budgets sharing a spend record, like in separate processes
+	budgets := []*Budget{
+		{MaxCredits: map[string]float64{AllEndpoints: 5}, Path: path},
+		{MaxCredits: map[string]float64{AllEndpoints: 5}, Path: path},
+	}
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(b *Budget) {
+			defer wg.Done()
+			reservation, err := b.reserve("domain-search")
+			if errors.Is(err, ErrBudgetExceeded) {
+				return
+			}
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			reserved++
+			mu.Unlock()
+			if err := b.settle(reservation, "domain-search", 1); err != nil {
+				t.Error(err)
+			}
+		}(budgets[i%2])
+	}
+	wg.Wait()
+	if reserved != 5 {
+		t.Errorf("expected 5 calls within the budget, got %d", reserved)
+	}
+	if spent, err := budgets[0].Spent(AllEndpoints); err != nil || spent != 5 {
+		t.Errorf("expected 5 credits spent, got %g and %v", spent, err)
+	}
+}
+
+func TestClient_BudgetWriteError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spend.json")
+	client := New("test-key", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			// the spend was reserved, but can't be recorded once the call is made
+			if err := os.Remove(path); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Mkdir(path, 0700); err != nil {
+				t.Fatal(err)
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"data":{"emails":[{"value":"patrick@stripe.com"}]}}`)),
+				Request:    req,
+			}, nil
+		}),
+	})
+	client.Budget = &Budget{MaxCredits: map[string]float64{AllEndpoints: 5}, Path: path}
+	var recordErrs []error
+	client.OnRecordError = func(err error) {
+		recordErrs = append(recordErrs, err)
+	}
+	result, err := client.DomainSearch(Params{"domain": "stripe.com"})
+	if err != nil {
+		t.Fatal("expected the call to succeed, got:", err)
+	}
+	if len(result.Data.Emails) != 1 {
+		t.Error("expected the result to be kept")
+	}
+	if len(recordErrs) != 1 {
+		t.Error("expected the budget error to be reported, got:", recordErrs)
+	}
+}
@@ -1,20 +1,75 @@
 package hunter
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"log"
 	"net/http"
-	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Client implements an object to interact with
 // the https://hunter.io API v2
+//
+// Its fields are read by every call without synchronization, so they
+// shouldn't be changed once the client is making calls. Set them using
+// the options of NewClient instead, or change a copy made by Clone.
 type Client struct {
-	Key    string
-	client *http.Client
+	// Key is the API key used by calls.
+	//
+	// Deprecated: Setting Key races with the calls in flight. Use
+	// WithAPIKey, SetKey or WithKey instead.
+	Key string
+	// BaseURL is the URL the API endpoints are relative to,
+	// which is DefaultBaseURL if empty.
+	BaseURL string
+	// Strict makes requests fail with an error wrapping ErrUnknownField
+	// when a response contains a field the result type doesn't model,
+	// which is useful to notice changes to the API.
+	Strict bool
+	// Ledger, if set, records every call the client makes.
+	Ledger *Ledger
+	// OnRecordError, if set, is called with the errors recording a call,
+	// like when the ledger or the budget's spend record can't be written. They don't fail the call,
+	// since its credits are already spent, and are logged with the log
+	// package if it isn't set.
+	OnRecordError func(err error)
+	// Budget, if set, limits the credits the client may spend. Calls
+	// exceeding it fail with an error wrapping ErrBudgetExceeded.
+	Budget *Budget
+	// DryRun, if set, makes every call describe the request it would send
+	// to the writer, with the API key redacted, and return ErrDryRun
+	// instead of sending it.
+	DryRun io.Writer
+	// KeyPool, if set, is used instead of Key to spread calls across the
+	// keys of several accounts.
+	KeyPool *KeyPool
+	// RegistrableDomain makes domain params be reduced to the domain which
+	// can be registered, like "stripe.com" for "blog.stripe.com", after
+	// they are normalized using NormalizeDomain.
+	RegistrableDomain bool
+	// Middleware wraps every call the client makes, the first being the
+	// outermost, like to log them, measure them or add headers.
+	Middleware []Middleware
+
+	mu          sync.RWMutex // guards Key and keyProvider
+	keyProvider func(ctx context.Context) (string, error)
+	client      *http.Client
+	timeout     time.Duration
+	userAgent   string
+	flights     flightGroup
 }
 
+// DefaultBaseURL is the URL of the hunter.io API v2.
+const DefaultBaseURL = "https://api.hunter.io/v2"
+
 var (
 	// UseDefaultEnvVariable is a default variable to tell the New method to lookup
 	// the HUNTER_API_KEY environment variable.
@@ -24,15 +79,14 @@ var (
 	UseDefaultHTTPClient = http.DefaultClient
 )
 
-// New returns a Client object.
+// New returns a Client object using the key, or the HUNTER_API_KEY
+// environment variable if UseDefaultEnvVariable, and the HTTP client.
+// It is the same as NewClient with the WithAPIKey and WithHTTPClient options.
 func New(key string, client *http.Client) *Client {
-	if client == nil {
-		client = UseDefaultHTTPClient
-	}
 	if key == UseDefaultEnvVariable {
-		key = os.Getenv("HUNTER_API_KEY")
+		return NewClient(WithHTTPClient(client))
 	}
-	return &Client{Key: key, client: client}
+	return NewClient(WithAPIKey(key), WithHTTPClient(client))
 }
 
 var (
@@ -45,16 +99,204 @@ var (
 	ErrTooManyRequests            = errors.New("you have reached your usage limit. Upgrade your plan if necessary")
 	ErrUnavailableForLegalReasons = errors.New("the person behind the requested resource asked directly or indirectly to stop the processing of this resource")
 	ErrServerError                = errors.New("something went wrong on hunter's end")
+	ErrUnknownField               = errors.New("the response contains a field unknown to the client")
 )
 
-func (c *Client) request(ctx context.Context, method, path string, params Params) ([]byte, error) {
+// APIError is returned when the API responds with an error status, like
+// ErrTooManyRequests, which it wraps along with the metadata of the
+// response, like the time the rate limit resets.
+type APIError struct {
+	Err  error
+	Meta *ResponseMeta
+}
+
+// Error returns the message of the wrapped error.
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error, so errors.Is(err, ErrTooManyRequests)
+// is true for an *APIError wrapping it.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// result is implemented by the types endpoint responses are decoded into.
+type result interface {
+	setResponse(body []byte, meta *ResponseMeta)
+	response() ([]byte, *ResponseMeta)
+	credits() float64
+}
+
+// call requests the endpoint, like "domain-search", with the given params and decodes the
+// response into the result, within the budget if any, recording the
+// call in the ledger if any. Identical calls in flight at the same time
+// are collapsed into one, whose result is shared.
+func (c *Client) call(ctx context.Context, endpoint string, params Params, res result) error {
+	params, originalDomain, domain, err := c.normalizeParams(params)
+	if err != nil {
+		return err
+	}
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	url := strings.TrimSuffix(baseURL, "/") + "/" + endpoint
+	var key string
+	if c.KeyPool == nil {
+		if key, err = c.apiKey(ctx); err != nil {
+			return err
+		}
+	}
+	err = c.flights.do(ctx, flightKey(url, key, params), res, func(ctx context.Context, result result) error {
+		handler := func(ctx context.Context, call *Call) (*ResponseMeta, error) {
+			meta, err := c.callURL(ctx, call, key, url, result)
+			if err == nil {
+				call.Result = result
+			}
+			return meta, err
+		}
+		_, err := chain(c.Middleware, handler)(ctx, newCall(endpoint, params))
+		return err
+	})
+	// the metadata is copied for each caller, as a collapsed call shares
+	// it, with the domain each caller gave
+	ownMeta := func(meta *ResponseMeta) *ResponseMeta {
+		own := *meta
+		own.OriginalDomain, own.Domain = originalDomain, domain
+		return &own
+	}
+	if body, meta := res.response(); meta != nil {
+		res.setResponse(body, ownMeta(meta))
+	}
+	if apiErr, ok := err.(*APIError); ok && apiErr.Meta != nil {
+		err = &APIError{Err: apiErr.Err, Meta: ownMeta(apiErr.Meta)}
+	}
+	return err
+}
+
+// Collapsed returns the number of calls which were collapsed into an
+// identical call already in flight, so didn't send a request of their own.
+func (c *Client) Collapsed() int64 {
+	c.flights.mu.Lock()
+	defer c.flights.mu.Unlock()
+	return c.flights.collapsed
+}
+
+// callURL sends the call to the endpoint's URL with the key, unless the
+// key pool is used, within the budget if any, recording it in the ledger
+// if any.
+func (c *Client) callURL(ctx context.Context, call *Call, key, url string, result result) (*ResponseMeta, error) {
+	var reservation string
+	if c.Budget != nil {
+		var err error
+		if reservation, err = c.Budget.reserve(call.Endpoint); err != nil {
+			return nil, err
+		}
+	}
+	meta, err := c.send(ctx, key, url, call.Params, call.Header, result)
+	if c.Budget != nil {
+		var credits float64
+		if meta != nil {
+			credits = meta.Credits
+		}
+		if berr := c.Budget.settle(reservation, call.Endpoint, credits); berr != nil {
+			c.recordError(fmt.Errorf("hunter: cannot record the spend in the budget: %w", berr))
+		}
+	}
+	if c.Ledger != nil && meta != nil {
+		if lerr := c.Ledger.Record(newLedgerEntry(meta, call.Params, err)); lerr != nil {
+			c.recordError(fmt.Errorf("hunter: cannot record the call in the ledger: %w", lerr))
+		}
+	}
+	return meta, err
+}
+
+// recordError reports an error recording a call.
+func (c *Client) recordError(err error) {
+	if c.OnRecordError != nil {
+		c.OnRecordError(err)
+		return
+	}
+	log.Println(err)
+}
+
+// send sends the request using the given key, or keys from the key pool
+// until one isn't rejected or rate limited, and decodes the response.
+func (c *Client) send(ctx context.Context, key, url string, params Params, header http.Header, result result) (*ResponseMeta, error) {
+	if c.KeyPool == nil {
+		return c.sendWithKey(ctx, key, url, params, header, result)
+	}
+	var (
+		tried   = map[*PoolKey]bool{}
+		lastErr error
+		last    *ResponseMeta
+	)
+	for {
+		key, err := c.KeyPool.next(tried)
+		if err != nil {
+			if lastErr != nil {
+				return last, lastErr
+			}
+			return nil, err
+		}
+		tried[key] = true
+		meta, err := c.sendWithKey(ctx, key.Key, url, params, header, result)
+		c.KeyPool.done(key, meta, err)
+		if !retryWithNextKey(err) {
+			return meta, err
+		}
+		last, lastErr = meta, err
+	}
+}
+
+// sendWithKey sends the request using the given key and decodes the response.
+func (c *Client) sendWithKey(ctx context.Context, key, url string, params Params, header http.Header, result result) (*ResponseMeta, error) {
+	body, meta, err := c.request(ctx, http.MethodGet, url, key, params, header)
+	if meta != nil {
+		meta.KeyID = KeyID(key)
+	}
+	if err != nil {
+		return meta, err
+	}
+	result.setResponse(body, meta)
+	if err := c.decode(body, result); err != nil {
+		return meta, err
+	}
+	meta.Credits = result.credits()
+	return meta, nil
+}
+
+// decode decodes the response body into the result, reporting
+// unknown fields if the client is strict.
+func (c *Client) decode(body []byte, result interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if c.Strict {
+		dec.DisallowUnknownFields()
+	}
+	err := dec.Decode(result)
+	if err != nil && c.Strict && strings.HasPrefix(err.Error(), "json: unknown field") {
+		return fmt.Errorf("%w: %s", ErrUnknownField, strings.TrimPrefix(err.Error(), "json: unknown field "))
+	}
+	return err
+}
+
+// request sends a request to the API with the given headers, returning
+// the response body and metadata describing the response.
+func (c *Client) request(ctx context.Context, method, path, key string, params Params, header http.Header) ([]byte, *ResponseMeta, error) {
 	req, err := http.NewRequest(http.MethodGet, path, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	req = req.WithContext(ctx)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	for name, values := range header {
+		req.Header[name] = append(req.Header[name], values...)
+	}
 	q := req.URL.Query()
-	q.Add("api_key", c.Key)
+	q.Add("api_key", key)
 	for k, v := range params {
 		// skip if value is empty
 		if v == "" {
@@ -63,31 +305,40 @@ func (c *Client) request(ctx context.Context, method, path string, params Params
 		q.Add(k, v)
 	}
 	req.URL.RawQuery = q.Encode()
+	if c.DryRun != nil {
+		if err := writeDryRun(c.DryRun, req); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrDryRun
+	}
+	start := now()
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, redactError(err)
 	}
 	defer resp.Body.Close()
+	meta := newResponseMeta(path, resp, start)
 	switch resp.StatusCode {
 	case 200, 201:
-		return ioutil.ReadAll(resp.Body)
+		body, err := ioutil.ReadAll(resp.Body)
+		return body, meta, err
 	case 204:
-		return nil, ErrNoContent
+		return nil, meta, &APIError{Err: ErrNoContent, Meta: meta}
 	case 400:
-		return nil, ErrBadRequest
+		return nil, meta, &APIError{Err: ErrBadRequest, Meta: meta}
 	case 401:
-		return nil, ErrUnauthorized
+		return nil, meta, &APIError{Err: ErrUnauthorized, Meta: meta}
 	case 403:
-		return nil, ErrForbidden
+		return nil, meta, &APIError{Err: ErrForbidden, Meta: meta}
 	case 404:
-		return nil, ErrNotFound
+		return nil, meta, &APIError{Err: ErrNotFound, Meta: meta}
 	case 422:
-		return nil, ErrUnprocessableEntity
+		return nil, meta, &APIError{Err: ErrUnprocessableEntity, Meta: meta}
 	case 429:
-		return nil, ErrTooManyRequests
+		return nil, meta, &APIError{Err: ErrTooManyRequests, Meta: meta}
 	case 451:
-		return nil, ErrUnavailableForLegalReasons
+		return nil, meta, &APIError{Err: ErrUnavailableForLegalReasons, Meta: meta}
 	default: // 5XX
-		return nil, ErrServerError
+		return nil, meta, &APIError{Err: ErrServerError, Meta: meta}
 	}
 }
@@ -1,6 +1,10 @@
 package hunter
 
 import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -11,3 +15,82 @@ func TestClient(t *testing.T) {
 		t.Fatal("no api key found for the client using the HUNTER_API_KEY environment variable")
 	}
 }
+
+// roundTripFunc is an http.RoundTripper used to test the client offline.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTestClient returns a client whose requests all get the given response.
+func newTestClient(status int, body string) *Client {
+	return New("test-key", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	})
+}
+
+func TestClient_Raw(t *testing.T) {
+	body := `{"data":{"email":"steli@close.io","status":"valid","new_field":{"nested":true}},"meta":{"params":{"email":"steli@close.io"}}}`
+	result, err := newTestClient(200, body).VerifyEmail(Params{"email": "steli@close.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result.Raw) != body {
+		t.Errorf("expected the raw body to be kept, got: %s", result.Raw)
+	}
+	if result.Data.Status != VerificationStatusValid {
+		t.Error("expected the typed result to be decoded, got:", result.Data.Status)
+	}
+}
+
+func TestClient_Strict(t *testing.T) {
+	client := newTestClient(200, `{"data":{"email":"steli@close.io","new_field":true}}`)
+	if _, err := client.VerifyEmail(Params{"email": "steli@close.io"}); err != nil {
+		t.Fatal("expected unknown fields to be ignored by default, got:", err)
+	}
+	client.Strict = true
+	_, err := client.VerifyEmail(Params{"email": "steli@close.io"})
+	if !errors.Is(err, ErrUnknownField) {
+		t.Fatal("expected ErrUnknownField, got:", err)
+	}
+	if !strings.Contains(err.Error(), "new_field") {
+		t.Error("expected the unknown field to be named, got:", err)
+	}
+}
+
+func TestClient_BaseURL(t *testing.T) {
+	var urls []string
+	client := New("test-key", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.URL.Scheme+"://"+req.URL.Host+req.URL.Path)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"data":{}}`)),
+				Request:    req,
+			}, nil
+		}),
+	})
+	if _, err := client.Account(); err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = "http://localhost:8080/v2/"
+	result, err := client.Account()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"https://api.hunter.io/v2/account", "http://localhost:8080/v2/account"}
+	if strings.Join(urls, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected requests to %v, got: %v", expected, urls)
+	}
+	if result.Response.Endpoint != "account" {
+		t.Error("expected the endpoint to be named account, got:", result.Response.Endpoint)
+	}
+}
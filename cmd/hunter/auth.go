@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/picatz/hunter"
+	"github.com/spf13/cobra"
+)
+
+// defaultCredentialsProfile is the name keys are stored under
+// when no profile is used.
+const defaultCredentialsProfile = "default"
+
+// errInvalidCredentials is returned when the credentials file can't be decoded.
+var errInvalidCredentials = errors.New("invalid credentials file")
+
+// credentials stores the API key of each profile, either in a file only
+// readable by the user, or using an external credential helper command.
+type credentials struct {
+	// Path is the credentials file, a JSON object of keys by profile name.
+	Path string
+	// Helper, if set, is a command run with the arguments "get", "store" or
+	// "erase" and the profile name. It prints the key for "get", and reads
+	// it from STDIN for "store".
+	Helper string
+	// Profile is the name of the profile to get, store or erase the key of.
+	Profile string
+}
+
+// defaultCredentialsPath returns the path of the credentials
+// file, next to the config file.
+func defaultCredentialsPath() string {
+	return filepath.Join(filepath.Dir(defaultConfigPath()), "credentials.json")
+}
+
+func (c *credentials) profile() string {
+	if c.Profile == "" {
+		return defaultCredentialsProfile
+	}
+	return c.Profile
+}
+
+// get returns the stored key, or an empty string if there isn't one.
+func (c *credentials) get() (string, error) {
+	if c.Helper != "" {
+		out, err := c.runHelper("get", "")
+		return strings.TrimSpace(out), err
+	}
+	keys, err := c.load()
+	if err != nil {
+		return "", err
+	}
+	return keys[c.profile()], nil
+}
+
+// store saves the key, replacing the stored one.
+func (c *credentials) store(key string) error {
+	if c.Helper != "" {
+		_, err := c.runHelper("store", key+"\n")
+		return err
+	}
+	keys, err := c.load()
+	if errors.Is(err, errInvalidCredentials) {
+		// storing a key is how a broken file is repaired
+		fmt.Fprintf(os.Stderr, "replacing the %v\n", err)
+		keys, err = map[string]string{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	keys[c.profile()] = key
+	return c.save(keys)
+}
+
+// erase removes the stored key, returning false if there wasn't one.
+func (c *credentials) erase() (bool, error) {
+	if c.Helper != "" {
+		_, err := c.runHelper("erase", "")
+		return err == nil, err
+	}
+	keys, err := c.load()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := keys[c.profile()]; !ok {
+		return false, nil
+	}
+	delete(keys, c.profile())
+	if len(keys) == 0 {
+		return true, os.Remove(c.Path)
+	}
+	return true, c.save(keys)
+}
+
+func (c *credentials) load() (map[string]string, error) {
+	keys := map[string]string{}
+	data, err := ioutil.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("%w %s: %v", errInvalidCredentials, c.Path, err)
+	}
+	return keys, nil
+}
+
+func (c *credentials) save(keys map[string]string) error {
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(c.Path, data, 0600); err != nil {
+		return err
+	}
+	// the file may have existed with looser permissions
+	return os.Chmod(c.Path, 0600)
+}
+
+// runHelper runs the credential helper with the action and profile name
+// as arguments, and the given input, returning what it printed.
+func (c *credentials) runHelper(action, input string) (string, error) {
+	cmd := exec.Command("sh", "-c", c.Helper+` "$@"`, "sh", action, c.profile())
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("running credential helper %s: %v", action, err)
+	}
+	return string(out), nil
+}
+
+// readKey prompts for an API key on STDERR and reads it from STDIN,
+// without echoing it when STDIN is a terminal.
+func readKey() (string, error) {
+	fmt.Fprint(os.Stderr, "API key: ")
+	if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+		if setEcho(false) == nil {
+			defer func() {
+				setEcho(true)
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func setEcho(on bool) error {
+	arg := "-echo"
+	if on {
+		arg = "echo"
+	}
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+func newAuthCommand(client *hunter.Client, useKeys func(), creds *credentials, keySource *string) *cobra.Command {
+	var cmdAuth = &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the stored API key",
+		Long:  "AUTH\n\nStores the API key, so it doesn't need to be kept in an environment variable or shell rc file. The key is stored for the current profile in a credentials file only readable by you, or using the config file's `credential_helper` command.\n\nA key given by the HUNTER_API_KEY environment variable or the profile takes precedence over the stored key.\n\n",
+	}
+
+	var cmdLogin = &cobra.Command{
+		Use:   "login",
+		Short: "Prompt for an API key, check it, and store it",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			key, err := readKey()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if key == "" {
+				fmt.Println("no API key given")
+				os.Exit(1)
+			}
+			client.SetKey(key)
+			client.KeyPool = nil
+			account, err := client.Account()
+			if errors.Is(err, hunter.ErrDryRun) {
+				return
+			}
+			if errors.Is(err, hunter.ErrUnauthorized) {
+				fmt.Println("the API key was rejected")
+				os.Exit(1)
+			}
+			if err != nil {
+				panic(err)
+			}
+			if err := creds.store(key); err != nil {
+				panic(err)
+			}
+			fmt.Printf("logged in as %s for the %s profile\n", account.Data.Email, creds.profile())
+			if os.Getenv("HUNTER_API_KEY") != "" {
+				fmt.Fprintln(os.Stderr, "the HUNTER_API_KEY environment variable is set, and is used instead of the stored key")
+			}
+		},
+	}
+
+	var cmdLogout = &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the stored API key",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			removed, err := creds.erase()
+			if err != nil {
+				panic(err)
+			}
+			if !removed {
+				fmt.Printf("no API key is stored for the %s profile\n", creds.profile())
+				return
+			}
+			fmt.Printf("removed the API key for the %s profile\n", creds.profile())
+		},
+	}
+
+	var cmdStatus = &cobra.Command{
+		Use:   "status",
+		Short: "Show the account, plan and calls remaining for the API key in use",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			useKeys()
+			if client.Key == "" && client.KeyPool == nil {
+				fmt.Println("not logged in, use `hunter auth login`")
+				os.Exit(1)
+			}
+			// the IDs of the keys of the pool are shown when it's used
+			label, keyIDs := "key", hunter.KeyID(client.Key)
+			if client.KeyPool != nil {
+				var ids []string
+				for _, k := range client.KeyPool.Keys() {
+					ids = append(ids, k.ID())
+				}
+				label, keyIDs = "keys", strings.Join(ids, ", ")
+			}
+			account, err := client.Account()
+			if errors.Is(err, hunter.ErrDryRun) {
+				return
+			}
+			if err != nil {
+				fmt.Printf("%s %s from %s: %v\n", label, keyIDs, *keySource, err)
+				os.Exit(1)
+			}
+			fmt.Printf("account:   %s %s <%s>\n", account.Data.FirstName, account.Data.LastName, account.Data.Email)
+			fmt.Printf("%-10s %s from %s\n", label+":", keyIDs, *keySource)
+			fmt.Printf("plan:      %s\n", account.Data.PlanName)
+			fmt.Printf("calls:     %d used, %d remaining of %d\n", account.Data.Calls.Used, account.Data.Calls.Available-account.Data.Calls.Used, account.Data.Calls.Available)
+			fmt.Printf("resets on: %s (in %d days)\n", account.Data.ResetDate, account.DaysUntilReset())
+		},
+	}
+
+	cmdAuth.AddCommand(cmdLogin)
+	cmdAuth.AddCommand(cmdLogout)
+	cmdAuth.AddCommand(cmdStatus)
+	return cmdAuth
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// openInput opens the file at the given path, or STDIN if the path is "-".
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return os.Stdin, nil
+	}
+	return os.Open(path)
+}
+
+// readInputLines reads the lines of the bulk input file, skipping blank
+// lines, comments starting with "#", and duplicates. It also returns the
+// total number of inputs read, including duplicates.
+func readInputLines(path string) ([]string, int, error) {
+	f, err := openInput(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	var (
+		lines []string
+		total int
+		seen  = map[string]bool{}
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		total++
+		key := strings.ToLower(line)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		lines = append(lines, line)
+	}
+	return lines, total, scanner.Err()
+}
+
+// readInputCSV reads the records of the bulk input CSV file, keyed by the
+// lowercased column names in its header, skipping duplicates. It also
+// returns the total number of records read, including duplicates.
+func readInputCSV(path string) ([]map[string]string, int, error) {
+	f, err := openInput(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	var (
+		records []map[string]string
+		total   int
+		seen    = map[string]bool{}
+	)
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		record := map[string]string{}
+		for i, value := range row {
+			if i < len(header) {
+				record[header[i]] = strings.TrimSpace(value)
+			}
+		}
+		total++
+		key := strings.ToLower(strings.Join(row, ","))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		records = append(records, record)
+	}
+	return records, total, nil
+}
+
+// confirmSpend asks for confirmation on STDERR before a bulk command makes
+// the given number of calls, if they could use more than the threshold of
+// credits. It returns true if the command should continue.
+func confirmSpend(calls int, credits, threshold float64, yes bool) bool {
+	fmt.Fprintf(os.Stderr, "this will make %d calls, using up to %g credits\n", calls, credits)
+	if yes || credits <= threshold {
+		return true
+	}
+	terminal, err := openTerminal()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "no terminal to ask for confirmation, use the `--yes` flag to continue")
+		return false
+	}
+	defer terminal.Close()
+	fmt.Fprintf(os.Stderr, "continue? [y/N] ")
+	answer, _ := bufio.NewReader(terminal).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// openTerminal opens the controlling terminal to read answers from, since
+// STDIN may be the input of a bulk command, or STDIN if it is a terminal.
+func openTerminal() (io.ReadCloser, error) {
+	if tty, err := os.Open("/dev/tty"); err == nil {
+		return tty, nil
+	}
+	if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+	return nil, errors.New("no terminal")
+}
+
+// parseBudget parses a budget like "100/day" into its credits and period.
+// The period may be "hour", "day", "week", "month" (30 days), or a duration like "12h".
+func parseBudget(budget string) (float64, time.Duration, error) {
+	parts := strings.SplitN(budget, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid budget %q, must be like 100/day", budget)
+	}
+	credits, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || credits < 0 {
+		return 0, 0, fmt.Errorf("invalid budget credits %q", parts[0])
+	}
+	var period time.Duration
+	switch p := strings.TrimSpace(parts[1]); p {
+	case "hour":
+		period = time.Hour
+	case "day":
+		period = 24 * time.Hour
+	case "week":
+		period = 7 * 24 * time.Hour
+	case "month":
+		period = 30 * 24 * time.Hour
+	default:
+		period, err = time.ParseDuration(p)
+		if err != nil || period <= 0 {
+			return 0, 0, fmt.Errorf("invalid budget period %q, must be hour, day, week, month or a duration like 12h", p)
+		}
+	}
+	return credits, period, nil
+}
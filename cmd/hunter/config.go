@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// config is the CLI's config file, holding named profiles.
+type config struct {
+	// DefaultProfile is the profile used when neither the `--profile`
+	// flag nor the HUNTER_PROFILE environment variable is given.
+	DefaultProfile string              `json:"default_profile"`
+	Profiles       map[string]*profile `json:"profiles"`
+	// CredentialHelper is a command storing the keys saved by
+	// `hunter auth login`, instead of the credentials file.
+	CredentialHelper string `json:"credential_helper,omitempty"`
+}
+
+// profile holds the settings for an account, used unless they are
+// overridden by an environment variable or flag.
+type profile struct {
+	// Key is the API key, or KeyCommand is a shell command printing it.
+	Key        string `json:"key,omitempty"`
+	KeyCommand string `json:"key_command,omitempty"`
+	// Keys are used for a key pool, like the HUNTER_API_KEYS environment variable.
+	Keys    []string `json:"keys,omitempty"`
+	BaseURL string   `json:"base_url,omitempty"`
+	// Limit is the default of the search command's `--limit` flag.
+	Limit int `json:"limit,omitempty"`
+	// Output is either "json" (the default) or "raw", like the `--raw` flag.
+	Output     string  `json:"output,omitempty"`
+	Budget     string  `json:"budget,omitempty"`
+	MaxCredits float64 `json:"max_credits,omitempty"`
+	Tag        string  `json:"tag,omitempty"`
+}
+
+// defaultConfigPath returns the path of the config file, in the XDG
+// config directory, which is usually ~/.config/hunter/config.json.
+func defaultConfigPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "hunter", "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join("hunter", "config.json")
+	}
+	return filepath.Join(home, ".config", "hunter", "config.json")
+}
+
+// loadConfig reads the config file at the given path. A missing
+// file is the same as an empty one.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// profile returns the named profile, or the default profile if the name is
+// empty. It returns nil without an error if no profile was chosen.
+func (c *config) profile(name string) (string, *profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return "", nil, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown profile %q", name)
+	}
+	return name, p, nil
+}
+
+// key returns the profile's API key, running its key command if it has one.
+func (p *profile) key() (string, error) {
+	if p.KeyCommand == "" {
+		return p.Key, nil
+	}
+	out, err := exec.Command("sh", "-c", p.KeyCommand).Output()
+	if err != nil {
+		return "", fmt.Errorf("running key command: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// applyDefaults sets the flags the profile has a default for,
+// unless they were given on the command line.
+func (p *profile) applyDefaults(cmd *cobra.Command) error {
+	defaults := map[string]string{}
+	// the limit is only a default for the search command, since other
+	// commands use their --limit flag for other purposes
+	if p.Limit != 0 && cmd.Name() == "search" {
+		defaults["limit"] = strconv.Itoa(p.Limit)
+	}
+	switch p.Output {
+	case "", "json":
+	case "raw":
+		defaults["raw"] = "true"
+	default:
+		return fmt.Errorf("invalid profile output %q, must be json or raw", p.Output)
+	}
+	// a budget given by either flag replaces the profile's budget
+	if !cmd.Flags().Changed("budget") && !cmd.Flags().Changed("max-credits") {
+		if p.Budget != "" {
+			defaults["budget"] = p.Budget
+		}
+		if p.MaxCredits != 0 {
+			defaults["max-credits"] = strconv.FormatFloat(p.MaxCredits, 'g', -1, 64)
+		}
+	}
+	if p.Tag != "" && os.Getenv("HUNTER_TAG") == "" {
+		defaults["tag"] = p.Tag
+	}
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid profile %s %q: %v", name, value, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/picatz/hunter"
+	"github.com/picatz/hunter/validate"
+	"github.com/spf13/cobra"
+)
+
+// defaultDomainListPath returns the path of the refreshed list of
+// webmail and disposable domains, next to the default ledger.
+func defaultDomainListPath() string {
+	return filepath.Join(filepath.Dir(hunter.DefaultLedgerPath()), "domains.json")
+}
+
+// loadDomainList replaces the embedded list of webmail and disposable
+// domains with the refreshed one, if there is one.
+func loadDomainList(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	list := &validate.DomainList{}
+	if err := json.Unmarshal(data, list); err != nil {
+		return fmt.Errorf("invalid domain list %s: %v", path, err)
+	}
+	validate.Domains = list
+	return nil
+}
+
+// readDomainsFile reads a file (or STDIN if "-") of domains, one per line.
+func readDomainsFile(path string) ([]string, error) {
+	f, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return validate.ReadDomains(f)
+}
+
+func newDomainsCommand() *cobra.Command {
+	var cmdDomains = &cobra.Command{
+		Use:   "domains",
+		Short: "Classify domains as webmail, disposable or corporate offline",
+		Long:  "DOMAINS\n\nClassifies domains as webmail, like gmail.com, disposable, like mailinator.com, or corporate, using a list of domains embedded in the command-line application. The list can be refreshed from local files using the `refresh` command.\n\n",
+	}
+
+	var cmdClassify = &cobra.Command{
+		Use:   "classify <domain>...",
+		Short: "Print the class of each domain",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			for _, domain := range args {
+				fmt.Printf("%s\t%s\n", domain, validate.Classify(domain))
+			}
+		},
+	}
+
+	var (
+		cmdRefreshWebmailFlag    string
+		cmdRefreshDisposableFlag string
+		cmdRefreshAddFlag        bool
+		cmdRefreshResetFlag      bool
+	)
+
+	var cmdRefresh = &cobra.Command{
+		Use:   "refresh",
+		Short: "Update the list of webmail and disposable domains from local files",
+		Long:  "REFRESH\n\nReplaces the webmail or disposable domains with those in the given files, which have one domain per line, like the lists of disposable domains published online. Use `--add` to add them to the current list instead. The updated list is used by every later command.\n\n",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			path := defaultDomainListPath()
+			if cmdRefreshResetFlag {
+				if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					panic(err)
+				}
+				fmt.Println("using the embedded list of domains")
+				return
+			}
+			if cmdRefreshWebmailFlag == "" && cmdRefreshDisposableFlag == "" {
+				fmt.Println("missing the `--webmail` or `--disposable` flag")
+				os.Exit(1)
+			}
+			list := validate.Domains
+			for class, file := range map[validate.Class]string{
+				validate.ClassWebmail:    cmdRefreshWebmailFlag,
+				validate.ClassDisposable: cmdRefreshDisposableFlag,
+			} {
+				if file == "" {
+					continue
+				}
+				domains, err := readDomainsFile(file)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				if cmdRefreshAddFlag {
+					list.Add(class, domains...)
+				} else {
+					list.Replace(class, domains...)
+				}
+			}
+			data, err := json.Marshal(list)
+			if err != nil {
+				panic(err)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				panic(err)
+			}
+			if err := ioutil.WriteFile(path, data, 0600); err != nil {
+				panic(err)
+			}
+			fmt.Printf("%d webmail and %d disposable domains saved to %s\n", list.Len(validate.ClassWebmail), list.Len(validate.ClassDisposable), path)
+		},
+	}
+
+	cmdRefresh.Flags().StringVar(&cmdRefreshWebmailFlag, "webmail", "", "A file (or - for STDIN) of webmail domains, one per line.")
+	cmdRefresh.Flags().StringVar(&cmdRefreshDisposableFlag, "disposable", "", "A file (or - for STDIN) of disposable domains, one per line.")
+	cmdRefresh.Flags().BoolVar(&cmdRefreshAddFlag, "add", false, "Add the domains to the current list, instead of replacing it.")
+	cmdRefresh.Flags().BoolVar(&cmdRefreshResetFlag, "reset", false, "Go back to using the embedded list of domains.")
+
+	cmdDomains.AddCommand(cmdClassify)
+	cmdDomains.AddCommand(cmdRefresh)
+	return cmdDomains
+}
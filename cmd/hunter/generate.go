@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/picatz/hunter"
+	"github.com/picatz/hunter/pattern"
+	"github.com/spf13/cobra"
+)
+
+// generated is the output of the generate command for a person.
+type generated struct {
+	Domain    string   `json:"domain"`
+	FirstName string   `json:"first_name"`
+	LastName  string   `json:"last_name"`
+	Pattern   string   `json:"pattern"`
+	Emails    []string `json:"emails"`
+}
+
+func newGenerateCommand(client *hunter.Client, useKeys func(), planBulk func(endpoint string, total, unique, calls int), bulkError func(input string, err error)) *cobra.Command {
+	var (
+		cmdGenerateDomainFlag    string
+		cmdGenerateFirstNameFlag string
+		cmdGenerateLastNameFlag  string
+		cmdGeneratePatternFlag   string
+		cmdGenerateInputFlag     string
+	)
+
+	// patterns and patternErrs are the domains' patterns, or the errors
+	// fetching them, so the API is called once per domain.
+	var (
+		patterns    = map[string]*pattern.Pattern{}
+		patternErrs = map[string]error{}
+	)
+
+	// domainPattern returns the domain's pattern, given or fetched using the
+	// Domain Search endpoint.
+	domainPattern := func(domain, given string) (*pattern.Pattern, error) {
+		if given != "" {
+			return pattern.Parse(given)
+		}
+		if p, ok := patterns[domain]; ok {
+			return p, nil
+		}
+		if err, ok := patternErrs[domain]; ok {
+			return nil, err
+		}
+		useKeys()
+		result, err := client.DomainSearch(hunter.Params{"domain": domain, "limit": "1"})
+		if err == nil && result.Data.Pattern == "" {
+			err = fmt.Errorf("no pattern is known for %s", domain)
+		}
+		var p *pattern.Pattern
+		if err == nil {
+			p, err = pattern.Parse(result.Data.Pattern)
+		}
+		if err != nil {
+			patternErrs[domain] = err
+			return nil, err
+		}
+		patterns[domain] = p
+		return p, nil
+	}
+
+	generate := func(domain, firstName, lastName, given string) error {
+		domain, err := hunter.NormalizeDomain(domain)
+		if err != nil {
+			return err
+		}
+		p, err := domainPattern(domain, given)
+		if err != nil {
+			return err
+		}
+		emails := p.Generate(firstName, lastName, domain)
+		if len(emails) == 0 {
+			return fmt.Errorf("the pattern %s needs both the first and last names", p)
+		}
+		json, err := json.Marshal(generated{
+			Domain:    domain,
+			FirstName: firstName,
+			LastName:  lastName,
+			Pattern:   p.String(),
+			Emails:    emails,
+		})
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(json))
+		return nil
+	}
+
+	var cmdGenerate = &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a person's likely email addresses from their domain's pattern",
+		Long:  "GENERATE\n\nGenerates the likely email addresses of a person from the email pattern of their domain, like `{first}.{last}`, which is fetched once per domain using the Domain Search endpoint. The names may contain diacritics, hyphens, apostrophes and particles like `van der`, and each way of writing them gives a candidate, most likely first.\n\nUse the `--pattern` flag to give the pattern instead of fetching it.\n\n",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if cmdGenerateInputFlag != "" {
+				records, total, err := readInputCSV(cmdGenerateInputFlag)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				domains := map[string]bool{}
+				for _, record := range records {
+					if record["pattern"] != "" || cmdGeneratePatternFlag != "" {
+						continue
+					}
+					if domain, err := hunter.NormalizeDomain(record["domain"]); err == nil {
+						domains[domain] = true
+					}
+				}
+				planBulk("domain-search", total, len(records), len(domains))
+				for _, record := range records {
+					given := record["pattern"]
+					if given == "" {
+						given = cmdGeneratePatternFlag
+					}
+					err := generate(record["domain"], record["first_name"], record["last_name"], given)
+					if err != nil {
+						bulkError(strings.Join([]string{record["first_name"], record["last_name"], record["domain"]}, " "), err)
+					}
+				}
+				return
+			}
+			if cmdGenerateDomainFlag == "" {
+				fmt.Println("missing the `--domain` flag")
+				os.Exit(1)
+			}
+			if cmdGenerateFirstNameFlag == "" && cmdGenerateLastNameFlag == "" {
+				fmt.Println("missing the `--first-name` or `--last-name` flag")
+				os.Exit(1)
+			}
+			err := generate(cmdGenerateDomainFlag, cmdGenerateFirstNameFlag, cmdGenerateLastNameFlag, cmdGeneratePatternFlag)
+			if errors.Is(err, hunter.ErrDryRun) {
+				return
+			}
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmdGenerate.Flags().StringVar(&cmdGenerateDomainFlag, "domain", "", "Domain name of the company, used for emails.")
+	cmdGenerate.Flags().StringVar(&cmdGenerateFirstNameFlag, "first-name", "", "The person's first name.")
+	cmdGenerate.Flags().StringVar(&cmdGenerateLastNameFlag, "last-name", "", "The person's last name.")
+	cmdGenerate.Flags().StringVar(&cmdGeneratePatternFlag, "pattern", "", "The domain's email pattern, like `{first}.{last}` or `{f}{last}`, instead of fetching it from the API.")
+	cmdGenerate.Flags().StringVar(&cmdGenerateInputFlag, "input", "", "A CSV file (or - for STDIN) of people, with a header naming the columns: domain, first_name, last_name and optionally pattern. Each result is printed as a line of JSON.")
+
+	return cmdGenerate
+}
@@ -1,15 +1,28 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/picatz/hunter"
+	"github.com/picatz/hunter/name"
+	"github.com/picatz/hunter/validate"
 	"github.com/spf13/cobra"
 )
 
+// dnsTimeout is how long the DNS records of a domain are looked up for
+// before bulk verify gives up, and sends its addresses to the API anyway.
+const dnsTimeout = 5 * time.Second
+
 func main() {
 	// handle CTRL+C quit
 	cleanup := func() {
@@ -25,32 +38,164 @@ func main() {
 
 	client := hunter.New(hunter.UseDefaultEnvVariable, hunter.UseDefaultHTTPClient)
 
+	var (
+		rawFlag      bool
+		strictFlag   bool
+		registrable  bool
+		showMetaFlag bool
+		ledgerFlag   string
+		noLedgerFlag bool
+		tagFlag      string
+		maxCredits   float64
+		budgetFlag   string
+		confirmAbove float64
+		yesFlag      bool
+		dryRunFlag   bool
+		strategyFlag string
+		configFlag   string
+		profileFlag  string
+		creds        credentials
+		keySource    = "the HUNTER_API_KEY environment variable"
+		// keyProfile is the profile in use, if any, whose keys are
+		// only used once the API is called
+		keyProfile     *profile
+		keyProfileName string
+		keysOnce       sync.Once
+	)
+
+	// useKeys resolves the API keys before the first call to the API, so
+	// commands which don't call it neither run the key command or credential
+	// helper, nor refresh the key pool. A key from the environment takes
+	// precedence over the profile's, which takes precedence over the stored key.
+	useKeys := func() {
+		keysOnce.Do(func() {
+			key := os.Getenv("HUNTER_API_KEY")
+			var poolKeys []string
+			if key == "" && keyProfile != nil {
+				profileKey, err := keyProfile.key()
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				if profileKey != "" || len(keyProfile.Keys) > 0 {
+					keySource = "the " + keyProfileName + " profile"
+				}
+				key, poolKeys = profileKey, keyProfile.Keys
+			}
+			if keys := os.Getenv("HUNTER_API_KEYS"); keys != "" {
+				poolKeys = strings.Split(keys, ",")
+				keySource = "the HUNTER_API_KEYS environment variable"
+			}
+			if key == "" && len(poolKeys) == 0 {
+				var err error
+				key, err = creds.get()
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				keySource = "the credentials file " + creds.Path
+				if creds.Helper != "" {
+					keySource = "the credential helper"
+				}
+			}
+			client.SetKey(key)
+			if len(poolKeys) > 0 {
+				client.KeyPool = hunter.NewKeyPool(poolKeys...)
+				client.KeyPool.Strategy = strategyFlag
+				if !dryRunFlag {
+					if err := client.KeyPool.Refresh(context.Background(), client); err != nil {
+						fmt.Fprintln(os.Stderr, err)
+					}
+				}
+			}
+		})
+	}
+
+	// output prints the result as JSON, or the untouched API response body
+	// when the `--raw` flag is given. The response metadata is printed to
+	// STDERR when the `--show-meta` flag is given.
+	output := func(result interface{}, raw json.RawMessage, meta *hunter.ResponseMeta) {
+		if showMetaFlag && meta != nil {
+			printResponseMeta(os.Stderr, meta)
+		}
+		if rawFlag {
+			fmt.Println(string(raw))
+			return
+		}
+		json, err := json.Marshal(result)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(json))
+	}
+
+	// errorMeta prints the metadata of the response an API error came with
+	// to STDERR, like the rate limit, when the `--show-meta` flag is given.
+	errorMeta := func(err error) {
+		var apiErr *hunter.APIError
+		if showMetaFlag && errors.As(err, &apiErr) && apiErr.Meta != nil {
+			printResponseMeta(os.Stderr, apiErr.Meta)
+		}
+	}
+
+	// bulkError reports an error for one of the inputs of a bulk command,
+	// stopping the command if the budget has been exceeded.
+	bulkError := func(input string, err error) {
+		if errors.Is(err, hunter.ErrDryRun) {
+			return
+		}
+		errorMeta(err)
+		fmt.Fprintf(os.Stderr, "%s: %v\n", input, err)
+		if errors.Is(err, hunter.ErrBudgetExceeded) {
+			os.Exit(1)
+		}
+	}
+
+	// planBulk prints the plan for a bulk command making the given number of
+	// calls to the endpoint for its unique inputs, asking for confirmation if
+	// it could spend more than the `--confirm-above` flag's credits. During a
+	// dry run, the plan is printed with the description of each request instead.
+	planBulk := func(endpoint string, total, unique, calls int) {
+		credits := float64(calls) * hunter.EstimatedCredits(endpoint)
+		if dryRunFlag {
+			fmt.Printf("dry run: %d inputs, %d after removing duplicates, %d calls to %s using up to %g credits\n\n", total, unique, calls, endpoint, credits)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "read %d inputs, %d after removing duplicates\n", total, unique)
+		if !confirmSpend(calls, credits, confirmAbove, yesFlag) {
+			os.Exit(1)
+		}
+	}
+
 	var cmdAccount = &cobra.Command{
 		Use:   "account",
 		Short: "Get information regarding your hunter.io account",
 		Long:  "ACCOUNT\nDocumentation Taken From: https://hunter.io/api/v2/docs#account \n\nEnables you to get information regarding your Hunter account at any time. This API call is free.\n\n",
 		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
+			useKeys()
 			result, err := client.Account()
-			if err != nil {
-				panic(err)
+			if errors.Is(err, hunter.ErrDryRun) {
+				return
 			}
-			json, err := json.Marshal(result)
 			if err != nil {
+				errorMeta(err)
 				panic(err)
 			}
-			fmt.Println(string(json))
+			output(result, result.Raw, result.Response)
 		},
 	}
 
 	var (
-		cmdSearchDomainFlag     string
-		cmdSearchCompanyFlag    string
-		cmdSearchLimitFlag      string
-		cmdSearchOffsetFlag     string
-		cmdSearchTypeFlag       string
-		cmdSearchSeniorityFlag  string
-		cmdSearchDepartmentFlag string
+		cmdSearchDomainFlag             string
+		cmdSearchCompanyFlag            string
+		cmdSearchLimitFlag              int
+		cmdSearchOffsetFlag             int
+		cmdSearchTypeFlag               string
+		cmdSearchSeniorityFlag          []string
+		cmdSearchDepartmentFlag         []string
+		cmdSearchRequiredFieldFlag      []string
+		cmdSearchVerificationStatusFlag []string
 	)
 
 	var cmdSearch = &cobra.Command{
@@ -59,37 +204,52 @@ func main() {
 		Long:  "SEARCH\nDocumentation Taken From: https://hunter.io/api/v2/docs#domain-search \n\nSearch all the email addresses corresponding to one website or compan.\n\nEach response will return up to 100 emails. Use the `--offset` flag to get all of them. A new query is counted for calls returning at least one result.\n\nThe number of sources is limited to 20 for each email address. The `extracted_on` attribute of a source contains the date it was found for the first time, whereas the `last_seen_on` attribute contains the date it was found for the last time.\n\ntype returns the value `personal` or `generic`. A `generic` email address is a role-based email address, like contact@hunter.io. On the contrary, a `personal` email address is the address of someone in the company.\n\n`confidence` is our estimation of the probability the email address returned is correct. It depends on several criteria such as the number and quality of sources.\n\nNote that this API call is rate limited to 15 requests per second.\n\n* You must send at least the domain name or the company name. You can also send both.\n\n",
 		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			params := hunter.Params{
-				"domain":     cmdSearchDomainFlag,
-				"company":    cmdSearchCompanyFlag,
-				"limit":      cmdSearchLimitFlag,
-				"offset":     cmdSearchOffsetFlag,
-				"type":       cmdSearchTypeFlag,
-				"seniority":  cmdSearchSeniorityFlag,
-				"department": cmdSearchDepartmentFlag,
+			useKeys()
+			var verificationStatuses []hunter.VerificationStatus
+			for _, status := range cmdSearchVerificationStatusFlag {
+				verificationStatuses = append(verificationStatuses, hunter.VerificationStatus(status))
+			}
+			searchParams := &hunter.DomainSearchParams{
+				Domain:             cmdSearchDomainFlag,
+				Company:            cmdSearchCompanyFlag,
+				Limit:              cmdSearchLimitFlag,
+				Offset:             cmdSearchOffsetFlag,
+				Type:               cmdSearchTypeFlag,
+				Seniority:          cmdSearchSeniorityFlag,
+				Department:         cmdSearchDepartmentFlag,
+				RequiredField:      cmdSearchRequiredFieldFlag,
+				VerificationStatus: verificationStatuses,
 			}
+			params := searchParams.Params()
 			if params["domain"] == "" && params["company"] == "" {
 				fmt.Println("missing either the `--domain` or `--company` flag")
 				os.Exit(1)
 			}
+			if err := hunter.ValidateDomainSearchParams(params); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
 			result, err := client.DomainSearch(params)
-			if err != nil {
-				panic(err)
+			if errors.Is(err, hunter.ErrDryRun) {
+				return
 			}
-			json, err := json.Marshal(result)
 			if err != nil {
+				errorMeta(err)
 				panic(err)
 			}
-			fmt.Println(string(json))
+			output(result, result.Raw, result.Response)
 		},
 	}
 
 	cmdSearch.Flags().StringVar(&cmdSearchDomainFlag, "domain", "", "Domain name from which you want to find the email addresses. For example, `stripe.com`.")
 	cmdSearch.Flags().StringVar(&cmdSearchCompanyFlag, "company", "", "The company name from which you want to find the email addresses. For example, `stripe`. Note that you'll get better results by supplying the domain name as we won't have to find it. If you send a request with both the domain and the company name, we'll use the domain name. It doesn't need to be in lowercase.")
-	cmdSearch.Flags().StringVar(&cmdSearchLimitFlag, "limit", "10", "Specifies the max number of email addresses to return.")
-	cmdSearch.Flags().StringVar(&cmdSearchOffsetFlag, "offset", "0", "Specifies the number of email addresses to skip.")
-	cmdSearch.Flags().StringVar(&cmdSearchSeniorityFlag, "seniority", "", "Get only email addresses for people with the selected seniority level. The possible values are junior, senior or executive. Several seniority levels can be selected (delimited by a comma).")
-	cmdSearch.Flags().StringVar(&cmdSearchDepartmentFlag, "department", "", "Get only email addresses for people working in the selected department(s). The possible values are `executive`, `it`, `finance`, `management`, `sales`, `legal`, `support`, `hr`, `marketing` or `communication`. Several departments can be selected (comma-delimited).")
+	cmdSearch.Flags().IntVar(&cmdSearchLimitFlag, "limit", 10, "Specifies the max number of email addresses to return.")
+	cmdSearch.Flags().IntVar(&cmdSearchOffsetFlag, "offset", 0, "Specifies the number of email addresses to skip.")
+	cmdSearch.Flags().StringVar(&cmdSearchTypeFlag, "type", "", "Get only personal or generic email addresses. The possible values are `personal` or `generic`.")
+	cmdSearch.Flags().StringSliceVar(&cmdSearchSeniorityFlag, "seniority", nil, "Get only email addresses for people with the selected seniority level. The possible values are junior, senior or executive. Several seniority levels can be selected (delimited by a comma, or by repeating the flag).")
+	cmdSearch.Flags().StringSliceVar(&cmdSearchDepartmentFlag, "department", nil, "Get only email addresses for people working in the selected department(s). The possible values are `executive`, `it`, `finance`, `management`, `sales`, `legal`, `support`, `hr`, `marketing`, `communication`, `education`, `design`, `health` or `operations`. Several departments can be selected (delimited by a comma, or by repeating the flag).")
+	cmdSearch.Flags().StringSliceVar(&cmdSearchRequiredFieldFlag, "required-field", nil, "Get only email addresses that have the selected field(s) filled in. The possible values are `full_name`, `position` or `phone_number`. Several fields can be selected (delimited by a comma, or by repeating the flag).")
+	cmdSearch.Flags().StringSliceVar(&cmdSearchVerificationStatusFlag, "verification-status", nil, "Get only email addresses with the selected verification status(es). The possible values are `valid`, `accept_all` or `unknown`. Several statuses can be selected (delimited by a comma, or by repeating the flag).")
 
 	var (
 		cmdFindDomainFlag    string
@@ -97,40 +257,91 @@ func main() {
 		cmdFindFirstNameFlag string
 		cmdFindLastNameFlag  string
 		cmdFindFullNameFlag  string
+		cmdFindLinkedinFlag  string
+		cmdFindMaxDuration   int
+		cmdFindInputFlag     string
+		cmdFindSkipWebmail   bool
 	)
 
 	var cmdFind = &cobra.Command{
 		Use:   "find",
 		Short: "Generates or retrieves the most likely email address from a domain name, a first name and a last name",
-		Long:  "FIND\nDocumentation Taken From: https://hunter.io/api/v2/docs#email-finder \n\nGenerates or retrieves the most likely email address from a domain name, a first name and a last name.\n\nThe score returned is an estimation of the probability the email generated is correct.\n\nIf we have found the retrieved email address somewhere on the web, we display the sources here. The number of sources is limited to 20. The extracted_on attribute contains the date it was found for the first time, whereas the last_seen_on attribute contains the date it was found for the last time.\n\n* You must send at least the domain name or the company name. You can also send both.\n\n** You must send at least the first name and the last name or the full name.\nThe score returned is an estimation of the probability the email generated is correct.\n\nIf we have found the retrieved email address somewhere on the web, we display the sources here. The number of sources is limited to 20. The `extracted_on attribute` contains the date it was found for the first time, whereas the `last_seen_on` attribute contains the date it was found for the last time.\n\n",
+		Long:  "FIND\nDocumentation Taken From: https://hunter.io/api/v2/docs#email-finder \n\nGenerates or retrieves the most likely email address from a domain name, a first name and a last name.\n\nThe score returned is an estimation of the probability the email generated is correct.\n\nIf we have found the retrieved email address somewhere on the web, we display the sources here. The number of sources is limited to 20. The extracted_on attribute contains the date it was found for the first time, whereas the last_seen_on attribute contains the date it was found for the last time.\n\n* You must send at least the domain name or the company name. You can also send both.\n\n** You must send at least the first name and the last name, the full name or the LinkedIn handle.\nThe score returned is an estimation of the probability the email generated is correct.\n\nIf we have found the retrieved email address somewhere on the web, we display the sources here. The number of sources is limited to 20. The `extracted_on attribute` contains the date it was found for the first time, whereas the `last_seen_on` attribute contains the date it was found for the last time.\n\n",
 		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			params := hunter.Params{
-				"domain":     cmdFindDomainFlag,
-				"company":    cmdFindCompanyFlag,
-				"first_name": cmdFindFirstNameFlag,
-				"last_name":  cmdFindLastNameFlag,
-				"full_name":  cmdFindFullNameFlag,
+			useKeys()
+			if cmdFindInputFlag != "" {
+				records, total, err := readInputCSV(cmdFindInputFlag)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				var found []map[string]string
+				for _, record := range records {
+					if cmdFindSkipWebmail && record["domain"] != "" && validate.Classify(record["domain"]) == validate.ClassWebmail {
+						fmt.Fprintf(os.Stderr, "%s: skipped, webmail domain\n", record["domain"])
+						continue
+					}
+					found = append(found, record)
+				}
+				planBulk("email-finder", total, len(records), len(found))
+				for _, record := range found {
+					maxDuration, _ := strconv.Atoi(record["max_duration"])
+					findParams := &hunter.EmailFinderParams{
+						Domain:         record["domain"],
+						Company:        record["company"],
+						FirstName:      record["first_name"],
+						LastName:       record["last_name"],
+						FullName:       record["full_name"],
+						LinkedinHandle: record["linkedin_handle"],
+						MaxDuration:    maxDuration,
+					}
+					splitFullName(findParams)
+					params := findParams.Params()
+					input := strings.Join([]string{params["full_name"], params["first_name"], params["last_name"], params["linkedin_handle"], params["domain"], params["company"]}, " ")
+					result, err := client.FindEmail(params)
+					if err != nil {
+						bulkError(strings.Join(strings.Fields(input), " "), err)
+						continue
+					}
+					output(result, result.Raw, result.Response)
+				}
+				return
 			}
+			findParams := &hunter.EmailFinderParams{
+				Domain:         cmdFindDomainFlag,
+				Company:        cmdFindCompanyFlag,
+				FirstName:      cmdFindFirstNameFlag,
+				LastName:       cmdFindLastNameFlag,
+				FullName:       cmdFindFullNameFlag,
+				LinkedinHandle: cmdFindLinkedinFlag,
+				MaxDuration:    cmdFindMaxDuration,
+			}
+			splitFullName(findParams)
+			params := findParams.Params()
 			if params["domain"] == "" && params["company"] == "" {
 				fmt.Println("missing either the `--domain` or `--company` flag")
 				os.Exit(1)
 			}
 			if params["first_name"] == "" || params["last_name"] == "" {
-				if params["full_name"] == "" {
-					fmt.Println("missing either the `--first-name` AND `--last-name` flags OR the `--full-name` flag")
+				if params["full_name"] == "" && params["linkedin_handle"] == "" {
+					fmt.Println("missing either the `--first-name` AND `--last-name` flags OR the `--full-name` flag OR the `--linkedin` flag")
 					os.Exit(1)
 				}
 			}
+			if err := hunter.ValidateEmailFinderParams(params); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
 			result, err := client.FindEmail(params)
-			if err != nil {
-				panic(err)
+			if errors.Is(err, hunter.ErrDryRun) {
+				return
 			}
-			json, err := json.Marshal(result)
 			if err != nil {
+				errorMeta(err)
 				panic(err)
 			}
-			fmt.Println(string(json))
+			output(result, result.Raw, result.Response)
 		},
 	}
 
@@ -138,18 +349,106 @@ func main() {
 	cmdFind.Flags().StringVar(&cmdFindCompanyFlag, "company", "", "The company name from which you want to find the email addresses.")
 	cmdFind.Flags().StringVar(&cmdFindFirstNameFlag, "first-name", "", "The person's first name. It doesn't need to be in lowercase.")
 	cmdFind.Flags().StringVar(&cmdFindLastNameFlag, "last-name", "", "The person's last name. It doesn't need to be in lowercase.")
-	cmdFind.Flags().StringVar(&cmdFindFullNameFlag, "full-name", "", "The person's full name. Note that you'll get better results by supplying the person's first and last name if you can. It doesn't need to be in lowercase.")
+	cmdFind.Flags().StringVar(&cmdFindFullNameFlag, "full-name", "", "The person's full name, which is split into their first and last name, handling particles like `van der`, suffixes like `Jr.` and reversed names like `Berg, Anna`. It doesn't need to be in lowercase.")
+	cmdFind.Flags().StringVar(&cmdFindLinkedinFlag, "linkedin", "", "The person's LinkedIn handle or profile URL. For example, `dustinmoskovitz` or `https://www.linkedin.com/in/dustinmoskovitz`. Can be used instead of the person's name.")
+	cmdFind.Flags().IntVar(&cmdFindMaxDuration, "max-duration", 0, "The maximum number of seconds (between 3 and 20) the request may take. A longer duration allows more thorough checks.")
+	cmdFind.Flags().BoolVar(&cmdFindSkipWebmail, "skip-webmail", false, "Skip the people of the `--input` file at webmail domains, like gmail.com, without calling the API.")
+	cmdFind.Flags().StringVar(&cmdFindInputFlag, "input", "", "A CSV file (or - for STDIN) of people to find, with a header naming the columns: domain, company, first_name, last_name, full_name, linkedin_handle and max_duration. Each result is printed as a line of JSON.")
 
 	var (
-		cmdVerifyEmailFlag string
+		cmdVerifyEmailFlag      string
+		cmdVerifyInputFlag      string
+		cmdVerifyNoPrecheckFlag bool
+		cmdVerifySkipWebmail    bool
 	)
 
+	// precheck validates the email address offline, reporting on STDERR
+	// why it's invalid, or if it's a role address or a likely typo. It
+	// returns false if the address clearly can't be delivered to.
+	precheck := func(email string) bool {
+		if cmdVerifyNoPrecheckFlag {
+			return true
+		}
+		check := validate.Email(email)
+		if !check.Valid() {
+			fmt.Fprintf(os.Stderr, "%s: skipped, %v\n", email, check.Err)
+		}
+		if check.Suggestion != "" {
+			fmt.Fprintf(os.Stderr, "%s: did you mean %s?\n", email, check.Suggestion)
+		}
+		if check.Valid() && check.Role {
+			fmt.Fprintf(os.Stderr, "%s: is a role address\n", email)
+		}
+		if check.Valid() && check.Class == validate.ClassDisposable {
+			fmt.Fprintf(os.Stderr, "%s: is a disposable address\n", email)
+		}
+		return check.Valid()
+	}
+
+	// isWebmail reports on STDERR if the bulk verify should
+	// skip the email address because it's a webmail address.
+	isWebmail := func(email string) bool {
+		if !cmdVerifySkipWebmail || validate.Email(email).Class != validate.ClassWebmail {
+			return false
+		}
+		fmt.Fprintf(os.Stderr, "%s: skipped, webmail address\n", email)
+		return true
+	}
+
+	// domains caches the DNS checks of the bulk verify's domains.
+	domains := &validate.DomainCache{}
+
+	// acceptsMail checks the DNS records of the email address' domain, reporting
+	// on STDERR if it can't receive email. Addresses whose domain couldn't
+	// be checked, like on a timeout, are still verified.
+	acceptsMail := func(email string) bool {
+		if cmdVerifyNoPrecheckFlag {
+			return true
+		}
+		domain := validate.Email(email).Domain
+		ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+		defer cancel()
+		check, err := domains.CheckDomain(ctx, domain)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: couldn't check the DNS records of %s: %v\n", email, domain, err)
+			return true
+		}
+		if !check.AcceptsMail() {
+			fmt.Fprintf(os.Stderr, "%s: skipped, %s has no MX or A records\n", email, domain)
+		}
+		return check.AcceptsMail()
+	}
+
 	var cmdVerify = &cobra.Command{
 		Use:   "verify",
 		Short: "Allows you to verify the deliverability of an email address",
 		Long:  "VERIFY\nDocumentation Taken From: https://hunter.io/api/v2/docs#email-verifier \n\nHunter focuses on B2B. Therefore, webmails are not verified. We'll run every check but won't reach the remote SMTP server.\n\nThis endpoint is rate-limited by domain name. You can check up to 200 email addresses for a domain name every 24 hours. You can check the number of requests remaining using the X-RateLimit-Remaining header.\n\nThe request will run for 20 seconds. If it was not able to provide a response in time, we will return a 202 status code. You will then be able to poll the same endpoint to get the verification's result. Of course, all the requests in this case are counted only once.\n\n\nReading Results:\n`score` is the deliverability score we give to the email address.\n`regexp` is true if the email address passes our regular expression.\n`gibberish` is true if we find this is an automatically generated email address (for example `e65rc109q@company.com`).\n`disposable` is true if we find this is an email address from a disposable email service.\n`webmail` is true if we find this is an email from a webmail (for example Gmail).\n`mx_records` is true if we find MX records exist on the domain of the given email address.\n`smtp_server` is true if we connect to the SMTP server successfully.\n`smtp_check` is true if the email address doesn't bounce.\n`accept_all` is true if the SMTP server accepts all the email addresses. It means you can have have false positives on SMTP checks.\n`block` is true if the SMTP server prevented us to perform the STMP check.\n`sources` If we have found the given email address somewhere on the web, we display the sources here. The number of sources is limited to 20.\n`extracted_on` contains the date it was found for the first time.\n`last_seen_on` contains the date it was found for the last time.\n\n",
 		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
+			useKeys()
+			if cmdVerifyInputFlag != "" {
+				emails, total, err := readInputLines(cmdVerifyInputFlag)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				var valid []string
+				for _, email := range emails {
+					if !isWebmail(email) && precheck(email) && acceptsMail(email) {
+						valid = append(valid, email)
+					}
+				}
+				planBulk("email-verifier", total, len(emails), len(valid))
+				for _, email := range valid {
+					result, err := client.VerifyEmail(hunter.Params{"email": email})
+					if err != nil {
+						bulkError(email, err)
+						continue
+					}
+					output(result, result.Raw, result.Response)
+				}
+				return
+			}
 			params := hunter.Params{
 				"email": cmdVerifyEmailFlag,
 			}
@@ -157,24 +456,150 @@ func main() {
 				fmt.Println("missing the `--email` flag")
 				os.Exit(1)
 			}
+			if !precheck(params["email"]) {
+				os.Exit(1)
+			}
 			result, err := client.VerifyEmail(params)
-			if err != nil {
-				panic(err)
+			if errors.Is(err, hunter.ErrDryRun) {
+				return
 			}
-			json, err := json.Marshal(result)
 			if err != nil {
+				errorMeta(err)
 				panic(err)
 			}
-			fmt.Println(string(json))
+			output(result, result.Raw, result.Response)
 		},
 	}
 
 	cmdVerify.Flags().StringVar(&cmdVerifyEmailFlag, "email", "", "The email address you want to verify.")
+	cmdVerify.Flags().BoolVar(&cmdVerifyNoPrecheckFlag, "no-precheck", false, "Send every email address to the API, without first checking offline that its syntax, domain and top-level domain are valid, or that the domain has MX or A records when verifying a file.")
+	cmdVerify.Flags().BoolVar(&cmdVerifySkipWebmail, "skip-webmail", false, "Skip the webmail addresses of the `--input` file, like gmail.com addresses, which Hunter doesn't check with their SMTP server anyway.")
+	cmdVerify.Flags().StringVar(&cmdVerifyInputFlag, "input", "", "A file (or - for STDIN) of email addresses to verify, one per line. Each result is printed as a line of JSON.")
 
-	var rootCmd = &cobra.Command{Use: "hunter"}
+	var rootCmd = &cobra.Command{
+		Use: "hunter",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			cfg, err := loadConfig(configFlag)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			profileName, profile, err := cfg.profile(profileFlag)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if profile != nil {
+				if err := profile.applyDefaults(cmd); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				client.BaseURL = profile.BaseURL
+			}
+			keyProfile, keyProfileName = profile, profileName
+			if err := loadDomainList(defaultDomainListPath()); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			client.Strict = strictFlag
+			client.RegistrableDomain = registrable
+			if strategyFlag != hunter.SelectLeastUsed && strategyFlag != hunter.SelectWeighted {
+				fmt.Printf("invalid `--key-strategy` %q, must be %s or %s\n", strategyFlag, hunter.SelectLeastUsed, hunter.SelectWeighted)
+				os.Exit(1)
+			}
+			creds = credentials{Path: defaultCredentialsPath(), Helper: cfg.CredentialHelper, Profile: profileName}
+			if dryRunFlag {
+				client.DryRun = os.Stdout
+			}
+			if !noLedgerFlag {
+				client.Ledger = hunter.NewLedger(ledgerFlag)
+				client.Ledger.Tag = tagFlag
+				client.Ledger.Profile = profileName
+			}
+			// calls which couldn't be recorded still print their results,
+			// with a single warning about the first error
+			var recordWarning sync.Once
+			client.OnRecordError = func(err error) {
+				recordWarning.Do(func() {
+					fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+				})
+			}
+			if maxCredits > 0 && budgetFlag != "" {
+				fmt.Println("use either the `--max-credits` or `--budget` flag")
+				os.Exit(1)
+			}
+			if maxCredits > 0 {
+				client.Budget = hunter.NewBudget(maxCredits)
+			}
+			if budgetFlag != "" {
+				credits, period, err := parseBudget(budgetFlag)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				client.Budget = &hunter.Budget{
+					MaxCredits: map[string]float64{hunter.AllEndpoints: credits},
+					Period:     period,
+					Path:       hunter.DefaultSpendRecordPath(),
+				}
+			}
+		},
+	}
+	rootCmd.PersistentFlags().StringVar(&configFlag, "config", defaultConfigPath(), "The config file holding the named profiles.")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", os.Getenv("HUNTER_PROFILE"), "The profile from the config file to use. Defaults to the HUNTER_PROFILE environment variable, then the config file's default_profile.")
+	rootCmd.PersistentFlags().BoolVar(&rawFlag, "raw", false, "Print the untouched response body from the API instead of the decoded result.")
+	rootCmd.PersistentFlags().BoolVar(&registrable, "registrable-domain", false, "Reduce domains to the domain which can be registered, like stripe.com for blog.stripe.com.")
+	rootCmd.PersistentFlags().BoolVar(&showMetaFlag, "show-meta", false, "Print the response metadata, like the rate limit and estimated credits used, to STDERR.")
+	rootCmd.PersistentFlags().BoolVar(&strictFlag, "strict", false, "Fail if the response contains fields unknown to the client, to notice changes to the API.")
+	rootCmd.PersistentFlags().StringVar(&ledgerFlag, "ledger", hunter.DefaultLedgerPath(), "The local ledger file every call is recorded in, used by the `usage` command.")
+	rootCmd.PersistentFlags().BoolVar(&noLedgerFlag, "no-ledger", false, "Don't record calls in the local ledger.")
+	rootCmd.PersistentFlags().StringVar(&tagFlag, "tag", os.Getenv("HUNTER_TAG"), "A tag recorded with every call in the ledger, like the name of the script making them. Defaults to the HUNTER_TAG environment variable.")
+	rootCmd.PersistentFlags().Float64Var(&maxCredits, "max-credits", 0, "The most credits this command may spend. Calls past it fail without being sent.")
+	rootCmd.PersistentFlags().StringVar(&budgetFlag, "budget", "", "The most credits that may be spent over a period, like `100/day`, shared by every command using it. The period may be hour, day, week, month or a duration like 12h.")
+	rootCmd.PersistentFlags().Float64Var(&confirmAbove, "confirm-above", 10, "Ask for confirmation before bulk commands that could spend more than this many credits.")
+	rootCmd.PersistentFlags().StringVar(&strategyFlag, "key-strategy", hunter.SelectLeastUsed, "How to select the key for each call when several are given using the HUNTER_API_KEYS environment variable, either `least-used` or weighted.")
+	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false, "Print the requests that would be sent, with the API key redacted, and their estimated credits, without sending them.")
+	rootCmd.PersistentFlags().BoolVarP(&yesFlag, "yes", "y", false, "Don't ask for confirmation before bulk commands.")
 	rootCmd.AddCommand(cmdAccount)
 	rootCmd.AddCommand(cmdSearch)
 	rootCmd.AddCommand(cmdFind)
 	rootCmd.AddCommand(cmdVerify)
+	rootCmd.AddCommand(newSchemaCommand())
+	rootCmd.AddCommand(newUsageCommand(&ledgerFlag))
+	rootCmd.AddCommand(newAuthCommand(client, useKeys, &creds, &keySource))
+	rootCmd.AddCommand(newGenerateCommand(client, useKeys, planBulk, bulkError))
+	rootCmd.AddCommand(newPatternCommand(client, useKeys))
+	rootCmd.AddCommand(newDomainsCommand())
 	rootCmd.Execute()
 }
+
+// splitFullName normalizes the names of the Email Finder params, and replaces
+// the full name with the first and last names parsed from it, which give
+// better results. A full name of a single word is kept.
+func splitFullName(p *hunter.EmailFinderParams) {
+	p.FirstName = name.Normalize(p.FirstName)
+	p.LastName = name.Normalize(p.LastName)
+	if p.FullName == "" || (p.FirstName != "" && p.LastName != "") {
+		return
+	}
+	n := name.Parse(p.FullName)
+	if n.First == "" || n.Last == "" {
+		return
+	}
+	p.FirstName, p.LastName, p.FullName = n.First, n.Last, ""
+}
+
+// printResponseMeta prints the response metadata as a single line of key=value pairs.
+func printResponseMeta(w io.Writer, meta *hunter.ResponseMeta) {
+	fmt.Fprintf(w, "endpoint=%s status=%d request_id=%q key=%s duration=%s credits=%g", meta.Endpoint, meta.StatusCode, meta.RequestID, meta.KeyID, meta.Duration, meta.Credits)
+	if meta.RateLimitLimit != 0 {
+		fmt.Fprintf(w, " rate_limit=%d rate_limit_remaining=%d", meta.RateLimitLimit, meta.RateLimitRemaining)
+	}
+	if !meta.RateLimitReset.IsZero() {
+		fmt.Fprintf(w, " rate_limit_reset=%s", meta.RateLimitReset.Format(time.RFC3339))
+	}
+	if meta.Domain != "" && meta.Domain != meta.OriginalDomain {
+		fmt.Fprintf(w, " domain=%s original_domain=%q", meta.Domain, meta.OriginalDomain)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/picatz/hunter"
+	"github.com/picatz/hunter/pattern"
+	"github.com/spf13/cobra"
+)
+
+// inferred is the output of the pattern command for a domain.
+type inferred struct {
+	Domain          string              `json:"domain"`
+	HunterPattern   string              `json:"hunter_pattern,omitempty"`
+	InferredPattern string              `json:"inferred_pattern,omitempty"`
+	Confidence      float64             `json:"confidence"`
+	Matches         int                 `json:"matches"`
+	Examples        int                 `json:"examples"`
+	Candidates      []pattern.Candidate `json:"candidates"`
+	// Agrees is set when both Hunter's and the inferred pattern are known.
+	Agrees *bool `json:"agrees,omitempty"`
+}
+
+func newPatternCommand(client *hunter.Client, useKeys func()) *cobra.Command {
+	var (
+		cmdPatternDomainFlag string
+		cmdPatternLimitFlag  int
+		cmdPatternInputFlag  string
+	)
+
+	var cmdPattern = &cobra.Command{
+		Use:   "pattern",
+		Short: "Infer a domain's email pattern from known names and email addresses",
+		Long:  "PATTERN\n\nInfers the most likely email pattern of a domain, like `{first}.{last}`, from the names and email addresses of people at the domain. It prints the pattern matching the most examples, the share of examples it matches as its confidence, and every pattern matching at least one example.\n\nThe examples are the email addresses found using the Domain Search endpoint with the `--domain` flag, and those in the `--input` CSV file, which doesn't call the API. When the Domain Search returns a pattern, it is cross-checked against the inferred one, and any disagreement is reported on STDERR.\n\n",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if cmdPatternDomainFlag == "" && cmdPatternInputFlag == "" {
+				fmt.Println("missing either the `--domain` or `--input` flag")
+				os.Exit(1)
+			}
+			examples := map[string][]pattern.Example{}
+			if cmdPatternInputFlag != "" {
+				records, _, err := readInputCSV(cmdPatternInputFlag)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				for _, record := range records {
+					email := record["email"]
+					at := strings.LastIndex(email, "@")
+					if at <= 0 {
+						fmt.Fprintf(os.Stderr, "%s: not an email address\n", email)
+						continue
+					}
+					// grouped by domain as normalized for the Domain Search
+					domain, err := hunter.NormalizeDomain(email[at+1:])
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "%s: %v\n", email, err)
+						continue
+					}
+					examples[domain] = append(examples[domain], pattern.Example{
+						FirstName: record["first_name"],
+						LastName:  record["last_name"],
+						Email:     email,
+					})
+				}
+			}
+			hunterPatterns := map[string]string{}
+			if cmdPatternDomainFlag != "" {
+				domain, err := hunter.NormalizeDomain(cmdPatternDomainFlag)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				useKeys()
+				result, err := client.DomainSearch(hunter.Params{"domain": domain, "limit": strconv.Itoa(cmdPatternLimitFlag)})
+				if errors.Is(err, hunter.ErrDryRun) {
+					return
+				}
+				if err != nil {
+					panic(err)
+				}
+				hunterPatterns[domain] = result.Data.Pattern
+				examples[domain] = append(examples[domain], result.PatternExamples()...)
+			}
+			domains := make([]string, 0, len(examples))
+			for domain := range examples {
+				domains = append(domains, domain)
+			}
+			sort.Strings(domains)
+			for _, domain := range domains {
+				out := inferred{Domain: domain, HunterPattern: hunterPatterns[domain], Examples: len(examples[domain])}
+				inference, err := pattern.Infer(examples[domain])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", domain, err)
+				} else {
+					out.InferredPattern = inference.Pattern.String()
+					out.Confidence = inference.Confidence
+					out.Matches = inference.Matches
+					out.Examples = inference.Examples
+					out.Candidates = inference.Candidates
+					if out.HunterPattern != "" {
+						agrees := inference.Agrees(out.HunterPattern)
+						out.Agrees = &agrees
+						if !agrees {
+							fmt.Fprintf(os.Stderr, "%s: Hunter's pattern %s matches %d of %d examples, but %s matches %d\n", domain, out.HunterPattern, inference.MatchesOf(out.HunterPattern), inference.Examples, inference.Pattern, inference.Matches)
+						}
+					}
+				}
+				json, err := json.Marshal(out)
+				if err != nil {
+					panic(err)
+				}
+				fmt.Println(string(json))
+			}
+		},
+	}
+
+	cmdPattern.Flags().StringVar(&cmdPatternDomainFlag, "domain", "", "Domain name to search for examples, and whose pattern according to Hunter is cross-checked.")
+	cmdPattern.Flags().IntVar(&cmdPatternLimitFlag, "limit", 100, "The max number of email addresses to get from the Domain Search as examples.")
+	cmdPattern.Flags().StringVar(&cmdPatternInputFlag, "input", "", "A CSV file (or - for STDIN) of examples, with a header naming the columns: first_name, last_name and email.")
+
+	return cmdPattern
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/picatz/hunter"
+	"github.com/picatz/hunter/schema"
+	"github.com/spf13/cobra"
+)
+
+// schemaResults maps the names accepted by `hunter schema check` to the
+// result type each endpoint's response is decoded into.
+var schemaResults = map[string]func() interface{}{
+	"account": func() interface{} { return new(hunter.AccountInformation) },
+	"search":  func() interface{} { return new(hunter.DomainSearchResult) },
+	"count":   func() interface{} { return new(hunter.EmailCounterResult) },
+	"find":    func() interface{} { return new(hunter.EmailFinderResult) },
+	"verify":  func() interface{} { return new(hunter.EmailVerifierResult) },
+}
+
+func newSchemaCommand() *cobra.Command {
+	var names []string
+	for name := range schemaResults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var cmdSchemaCheck = &cobra.Command{
+		Use:   "check <" + strings.Join(names, "|") + "> [file]",
+		Short: "Compare a response body to the client's result type for an endpoint",
+		Long:  "SCHEMA CHECK\n\nReads a response body from the given file, or from STDIN, and reports any fields that are missing, extra, or of the wrong type compared to the client's result type for the endpoint. Exits with a non-zero status if any are found.\n\nLive responses can be checked by piping a command's `--raw` output:\n\n  hunter search --domain stripe.com --raw | hunter schema check search\n\n",
+		Args:  cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			newResult, ok := schemaResults[args[0]]
+			if !ok {
+				fmt.Printf("unknown endpoint %q, must be one of: %s\n", args[0], strings.Join(names, ", "))
+				os.Exit(1)
+			}
+			var (
+				body []byte
+				err  error
+			)
+			if len(args) == 2 {
+				body, err = ioutil.ReadFile(args[1])
+			} else {
+				body, err = ioutil.ReadAll(os.Stdin)
+			}
+			if err != nil {
+				panic(err)
+			}
+			issues, err := schema.Check(body, newResult())
+			if err != nil {
+				fmt.Println("invalid response body:", err)
+				os.Exit(1)
+			}
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+			if len(issues) > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	var cmdSchema = &cobra.Command{
+		Use:   "schema",
+		Short: "Check API responses for changes the client doesn't know about",
+		Args:  cobra.NoArgs,
+	}
+	cmdSchema.AddCommand(cmdSchemaCheck)
+	return cmdSchema
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/picatz/hunter"
+	"github.com/spf13/cobra"
+)
+
+func newUsageCommand(ledgerPath *string) *cobra.Command {
+	var (
+		cmdUsageByFlag     string
+		cmdUsageOutputFlag string
+		cmdUsageSinceFlag  string
+	)
+
+	var cmdUsage = &cobra.Command{
+		Use:   "usage",
+		Short: "Summarize the calls recorded in the local usage ledger",
+		Long:  "USAGE\n\nEvery call made by the command-line application is recorded in a local ledger, with its endpoint, domain, estimated credits, profile and tag (set with the `--tag` flag or HUNTER_TAG environment variable). This command summarizes the ledger to show which scripts, people or domains used the account's calls.\n\nThe account-wide totals are available using the `account` command.\n\n",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			entries, err := hunter.NewLedger(*ledgerPath).Entries()
+			if err != nil {
+				panic(err)
+			}
+			if cmdUsageSinceFlag != "" {
+				since, err := time.Parse(hunter.DateFormat, cmdUsageSinceFlag)
+				if err != nil {
+					fmt.Println("the `--since` flag must be a date like 2006-01-02")
+					os.Exit(1)
+				}
+				var filtered []hunter.LedgerEntry
+				for _, entry := range entries {
+					if !entry.Time.Before(since) {
+						filtered = append(filtered, entry)
+					}
+				}
+				entries = filtered
+			}
+			switch cmdUsageByFlag {
+			case hunter.UsageByDay, hunter.UsageByEndpoint, hunter.UsageByDomain, hunter.UsageByTag, hunter.UsageByProfile:
+			default:
+				fmt.Println("the `--by` flag must be one of: day, endpoint, domain, tag, profile")
+				os.Exit(1)
+			}
+			summaries := hunter.SummarizeUsage(entries, cmdUsageByFlag)
+			switch cmdUsageOutputFlag {
+			case "json":
+				json, err := json.Marshal(summaries)
+				if err != nil {
+					panic(err)
+				}
+				fmt.Println(string(json))
+			case "csv":
+				w := csv.NewWriter(os.Stdout)
+				w.Write([]string{cmdUsageByFlag, "calls", "credits", "errors"})
+				for _, s := range summaries {
+					w.Write([]string{s.Key, strconv.Itoa(s.Calls), strconv.FormatFloat(s.Credits, 'f', -1, 64), strconv.Itoa(s.Errors)})
+				}
+				w.Flush()
+				if err := w.Error(); err != nil {
+					panic(err)
+				}
+			case "table":
+				w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+				fmt.Fprintf(w, "%s\tCALLS\tCREDITS\tERRORS\n", cmdUsageByFlag)
+				for _, s := range summaries {
+					key := s.Key
+					if key == "" {
+						key = "-"
+					}
+					fmt.Fprintf(w, "%s\t%d\t%g\t%d\n", key, s.Calls, s.Credits, s.Errors)
+				}
+				w.Flush()
+			default:
+				fmt.Println("the `--output` flag must be one of: table, json, csv")
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmdUsage.Flags().StringVar(&cmdUsageByFlag, "by", hunter.UsageByDay, "Group the calls by `day`, endpoint, domain, tag or profile.")
+	cmdUsage.Flags().StringVar(&cmdUsageOutputFlag, "output", "table", "The output format, either `table`, json or csv.")
+	cmdUsage.Flags().StringVar(&cmdUsageSinceFlag, "since", "", "Only include calls made on or after the given date, like `2006-01-02`.")
+	return cmdUsage
+}
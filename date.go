@@ -0,0 +1,62 @@
+package hunter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateFormat is the layout of dates sent by the API, like "2017-02-13".
+const DateFormat = "2006-01-02"
+
+// now returns the current time, and can be replaced in tests.
+var now = time.Now
+
+// Date is a calendar date sent by the API as a "2006-01-02" string.
+// The zero Date is sent as null.
+type Date struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes a "2006-01-02" string, an empty string or null.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), jsonNull) {
+		*d = Date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("hunter: cannot decode %s into a date", data)
+	}
+	if s == "" {
+		*d = Date{}
+		return nil
+	}
+	t, err := time.Parse(DateFormat, s)
+	if err != nil {
+		return fmt.Errorf("hunter: cannot decode %q into a date: %w", s, err)
+	}
+	*d = Date{Time: t}
+	return nil
+}
+
+// MarshalJSON encodes the date as a "2006-01-02" string, or null if it is zero.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return jsonNull, nil
+	}
+	return json.Marshal(d.String())
+}
+
+// String returns the date in the "2006-01-02" format.
+func (d Date) String() string {
+	return d.Format(DateFormat)
+}
+
+// daysBetween returns the number of whole calendar days from a to b.
+func daysBetween(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
@@ -0,0 +1,95 @@
+package hunter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func fixNow(t *testing.T, date string) {
+	t.Helper()
+	fixed, err := time.Parse(DateFormat, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestDate_JSON(t *testing.T) {
+	tests := []struct {
+		input, output string
+		zero          bool
+	}{
+		{`"2017-02-13"`, `"2017-02-13"`, false},
+		{`null`, `null`, true},
+		{`""`, `null`, true},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			var d Date
+			if err := json.Unmarshal([]byte(test.input), &d); err != nil {
+				t.Fatal(err)
+			}
+			if d.IsZero() != test.zero {
+				t.Errorf("expected zero date to be %v, got %v", test.zero, d)
+			}
+			b, err := json.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != test.output {
+				t.Errorf("marshaled to %s, want %s", b, test.output)
+			}
+		})
+	}
+	var d Date
+	if err := json.Unmarshal([]byte(`"13/02/2017"`), &d); err == nil {
+		t.Error("expected an error for an invalid date, got:", d)
+	}
+}
+
+func TestSource_Age(t *testing.T) {
+	fixNow(t, "2017-02-20")
+	var source Source
+	err := json.Unmarshal([]byte(`{"extracted_on":"2015-08-29","last_seen_on":"2017-02-13"}`), &source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if age := source.Age(); age != 7*24*time.Hour {
+		t.Error("expected the source to be 7 days old, got:", age)
+	}
+}
+
+func TestDomainEmail_MostRecentSource(t *testing.T) {
+	var email DomainEmail
+	if email.MostRecentSource() != nil {
+		t.Fatal("expected no source")
+	}
+	err := json.Unmarshal([]byte(`{"sources":[
+		{"uri":"a","extracted_on":"2015-08-29","last_seen_on":"2016-01-01"},
+		{"uri":"b","extracted_on":"2016-05-01","last_seen_on":"2017-02-13"},
+		{"uri":"c","extracted_on":"2017-01-01","last_seen_on":null}
+	]}`), &email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source := email.MostRecentSource(); source.URI != "b" {
+		t.Error("expected source b to be the most recent, got:", source.URI)
+	}
+}
+
+func TestAccountInformation_DaysUntilReset(t *testing.T) {
+	fixNow(t, "2019-01-20")
+	var account AccountInformation
+	if err := json.Unmarshal([]byte(`{"data":{"reset_date":"2019-02-01"}}`), &account); err != nil {
+		t.Fatal(err)
+	}
+	if days := account.DaysUntilReset(); days != 12 {
+		t.Error("expected 12 days until reset, got:", days)
+	}
+	fixNow(t, "2019-03-01")
+	if days := account.DaysUntilReset(); days != 0 {
+		t.Error("expected 0 days until a past reset, got:", days)
+	}
+}
@@ -0,0 +1,130 @@
+package hunter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/picatz/hunter/internal/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// NormalizeDomain returns the domain name given as a domain or URL, like
+// "https://www.Stripe.com/about" or "münchen.de:443", in the form the API
+// expects, like "stripe.com" or "xn--mnchen-3ya.de". The scheme, user, path,
+// port and "www." are removed, it is lowercased, and internationalized domain
+// names are converted to their ASCII form. It returns an error, wrapping
+// ErrInvalidParams, if the result isn't a valid domain name.
+func NormalizeDomain(input string) (string, error) {
+	domain := strings.TrimSpace(input)
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+3:]
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	if i := strings.LastIndex(domain, "@"); i >= 0 {
+		domain = domain[i+1:]
+	}
+	if i := strings.LastIndex(domain, ":"); i >= 0 && isDigits(domain[i+1:]) {
+		domain = domain[:i]
+	}
+	domain, err := idna.ToASCII(strings.TrimSuffix(domain, "."))
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid domain %q", ErrInvalidParams, input)
+	}
+	domain = strings.TrimPrefix(domain, "www.")
+	if !isDomainName(domain) {
+		return "", fmt.Errorf("%w: invalid domain %q", ErrInvalidParams, input)
+	}
+	return domain, nil
+}
+
+// RegistrableDomain returns the domain which can be registered that the
+// given domain or URL belongs to, like "stripe.com" for "blog.stripe.com" or
+// "bbc.co.uk" for "news.bbc.co.uk", using the public suffix list.
+// The domain is normalized using NormalizeDomain first.
+func RegistrableDomain(input string) (string, error) {
+	domain, err := NormalizeDomain(input)
+	if err != nil {
+		return "", err
+	}
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q is a public suffix", ErrInvalidParams, input)
+	}
+	return registrable, nil
+}
+
+// isDomainName returns true if the ASCII domain has at least two labels, made
+// of letters, digits and hyphens which don't start or end them.
+func isDomainName(domain string) bool {
+	if len(domain) > 253 {
+		return false
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// normalizeParams returns a copy of the params with their domain normalized,
+// as well as the domain of their email address, which is only lowercased and
+// converted to ASCII. The given and normalized domains are returned, which
+// are empty if the params have neither.
+func (c *Client) normalizeParams(params Params) (Params, string, string, error) {
+	var original, normalized string
+	var err error
+	switch {
+	case params["domain"] != "":
+		original = params["domain"]
+		if c.RegistrableDomain {
+			normalized, err = RegistrableDomain(original)
+		} else {
+			normalized, err = NormalizeDomain(original)
+		}
+	case strings.Contains(params["email"], "@"):
+		i := strings.LastIndex(params["email"], "@")
+		original = params["email"][i+1:]
+		normalized, err = idna.ToASCII(original)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid email domain %q", ErrInvalidParams, original)
+		}
+	default:
+		return params, "", "", nil
+	}
+	if err != nil {
+		return nil, "", "", err
+	}
+	normalizedParams := make(Params, len(params))
+	for k, v := range params {
+		normalizedParams[k] = v
+	}
+	if params["domain"] != "" {
+		normalizedParams["domain"] = normalized
+	} else {
+		email := params["email"]
+		normalizedParams["email"] = email[:strings.LastIndex(email, "@")+1] + normalized
+	}
+	return normalizedParams, original, normalized, nil
+}
@@ -1,86 +1,271 @@
 package hunter
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"net/http"
+	"fmt"
+	"strconv"
+
+	"github.com/picatz/hunter/pattern"
+)
+
+// Email types accepted by the "type" domain search parameter.
+const (
+	EmailTypePersonal = "personal"
+	EmailTypeGeneric  = "generic"
+)
+
+// Seniority levels accepted by the "seniority" domain search parameter.
+const (
+	SeniorityJunior    = "junior"
+	SenioritySenior    = "senior"
+	SeniorityExecutive = "executive"
+)
+
+// Departments accepted by the "department" domain search parameter.
+const (
+	DepartmentExecutive     = "executive"
+	DepartmentIT            = "it"
+	DepartmentFinance       = "finance"
+	DepartmentManagement    = "management"
+	DepartmentSales         = "sales"
+	DepartmentLegal         = "legal"
+	DepartmentSupport       = "support"
+	DepartmentHR            = "hr"
+	DepartmentMarketing     = "marketing"
+	DepartmentCommunication = "communication"
+	DepartmentEducation     = "education"
+	DepartmentDesign        = "design"
+	DepartmentHealth        = "health"
+	DepartmentOperations    = "operations"
 )
 
+// Fields accepted by the "required_field" domain search parameter.
+const (
+	RequiredFieldFullName    = "full_name"
+	RequiredFieldPosition    = "position"
+	RequiredFieldPhoneNumber = "phone_number"
+)
+
+// DomainSearchParams is a typed form of the parameters accepted by the
+// DomainSearch function. Use the Params method to get the Params to send.
+type DomainSearchParams struct {
+	Domain             string
+	Company            string
+	Limit              int
+	Offset             int
+	Type               string
+	Seniority          []string
+	Department         []string
+	RequiredField      []string
+	VerificationStatus []VerificationStatus
+}
+
+// Params returns the Params for the domain search, omitting empty values.
+func (p *DomainSearchParams) Params() Params {
+	params := Params{
+		"domain":         p.Domain,
+		"company":        p.Company,
+		"type":           p.Type,
+		"seniority":      joinList(p.Seniority),
+		"department":     joinList(p.Department),
+		"required_field": joinList(p.RequiredField),
+	}
+	var statuses []string
+	for _, status := range p.VerificationStatus {
+		statuses = append(statuses, string(status))
+	}
+	params["verification_status"] = joinList(statuses)
+	if p.Limit != 0 {
+		params["limit"] = strconv.Itoa(p.Limit)
+	}
+	if p.Offset != 0 {
+		params["offset"] = strconv.Itoa(p.Offset)
+	}
+	return params
+}
+
+// domainSearchParams returns the typed form of the domain search params.
+func domainSearchParams(params Params) *DomainSearchParams {
+	p := &DomainSearchParams{
+		Domain:        params["domain"],
+		Company:       params["company"],
+		Type:          params["type"],
+		Seniority:     splitList(params["seniority"]),
+		Department:    splitList(params["department"]),
+		RequiredField: splitList(params["required_field"]),
+	}
+	for _, status := range splitList(params["verification_status"]) {
+		p.VerificationStatus = append(p.VerificationStatus, VerificationStatus(status))
+	}
+	p.Limit, _ = strconv.Atoi(params["limit"])
+	p.Offset, _ = strconv.Atoi(params["offset"])
+	return p
+}
+
+// ValidateDomainSearchParams checks the given domain search parameters locally,
+// returning an error wrapping ErrInvalidParams if they would be rejected.
+func ValidateDomainSearchParams(params Params) error {
+	if params["domain"] == "" && params["company"] == "" {
+		return fmt.Errorf("%w: either domain or company is required", ErrInvalidParams)
+	}
+	if err := checkInt(params, "limit", 1, 100); err != nil {
+		return err
+	}
+	if err := checkInt(params, "offset", 0, 1<<31-1); err != nil {
+		return err
+	}
+	if err := checkOneOf(params, "type", EmailTypePersonal, EmailTypeGeneric); err != nil {
+		return err
+	}
+	if len(splitList(params["type"])) > 1 {
+		return fmt.Errorf("%w: only one type can be given", ErrInvalidParams)
+	}
+	if err := checkOneOf(params, "seniority", SeniorityJunior, SenioritySenior, SeniorityExecutive); err != nil {
+		return err
+	}
+	if err := checkOneOf(params, "department",
+		DepartmentExecutive, DepartmentIT, DepartmentFinance, DepartmentManagement,
+		DepartmentSales, DepartmentLegal, DepartmentSupport, DepartmentHR,
+		DepartmentMarketing, DepartmentCommunication, DepartmentEducation,
+		DepartmentDesign, DepartmentHealth, DepartmentOperations,
+	); err != nil {
+		return err
+	}
+	if err := checkOneOf(params, "required_field", RequiredFieldFullName, RequiredFieldPosition, RequiredFieldPhoneNumber); err != nil {
+		return err
+	}
+	return checkOneOf(params, "verification_status", string(VerificationStatusValid), string(VerificationStatusAcceptAll), string(VerificationStatusUnknown))
+}
+
+// DomainEmail is an email address found by a domain search.
+type DomainEmail struct {
+	Value        string       `json:"value"`
+	Type         string       `json:"type"`
+	Confidence   int          `json:"confidence"`
+	Sources      []Source     `json:"sources"`
+	FirstName    string       `json:"first_name"`
+	LastName     string       `json:"last_name"`
+	Position     string       `json:"position"`
+	Seniority    string       `json:"seniority"`
+	Department   string       `json:"department"`
+	Linkedin     NullString   `json:"linkedin"`
+	Twitter      string       `json:"twitter"`
+	PhoneNumber  NullString   `json:"phone_number"`
+	Verification Verification `json:"verification"`
+}
+
+// IsSafeToSend returns true if the email address was verified as valid.
+func (e *DomainEmail) IsSafeToSend() bool {
+	return e.Verification.Status.IsSafeToSend()
+}
+
+// MostRecentSource returns the most recently seen source for the
+// email address, or nil if there are none.
+func (e *DomainEmail) MostRecentSource() *Source {
+	return mostRecentSource(e.Sources)
+}
+
+// Pagination describes which page of results was returned. Use Offset and
+// Limit as the next request's "offset" to get the following page.
+type Pagination struct {
+	Results int `json:"results"`
+	Limit   int `json:"limit"`
+	Offset  int `json:"offset"`
+}
+
+// HasMore returns true if there are more results after this page.
+func (p Pagination) HasMore() bool {
+	return p.Offset+p.Limit < p.Results
+}
+
 // DomainSearchResult is returned by the DomainSearch function.
 type DomainSearchResult struct {
 	Data struct {
-		Domain       string `json:"domain"`
-		Disposable   bool   `json:"disposable"`
-		Webmail      bool   `json:"webmail"`
-		Pattern      string `json:"pattern"`
-		Organization string `json:"organization"`
-		Emails       []struct {
-			Value      string `json:"value"`
-			Type       string `json:"type"`
-			Confidence int    `json:"confidence"`
-			Sources    []struct {
-				Domain      string `json:"domain"`
-				URI         string `json:"uri"`
-				ExtractedOn string `json:"extracted_on"`
-				LastSeenOn  string `json:"last_seen_on"`
-				StillOnPage bool   `json:"still_on_page"`
-			} `json:"sources"`
-			FirstName   string      `json:"first_name"`
-			LastName    string      `json:"last_name"`
-			Position    string      `json:"position"`
-			Seniority   string      `json:"seniority"`
-			Department  string      `json:"department"`
-			Linkedin    interface{} `json:"linkedin"`
-			Twitter     string      `json:"twitter"`
-			PhoneNumber interface{} `json:"phone_number"`
-		} `json:"emails"`
+		Domain       string        `json:"domain"`
+		Disposable   bool          `json:"disposable"`
+		Webmail      bool          `json:"webmail"`
+		Pattern      string        `json:"pattern"`
+		Organization string        `json:"organization"`
+		Emails       []DomainEmail `json:"emails"`
 	} `json:"data"`
 	Meta struct {
-		Results int `json:"results"`
-		Limit   int `json:"limit"`
-		Offset  int `json:"offset"`
-		Params  struct {
-			Domain     string      `json:"domain"`
-			Company    interface{} `json:"company"`
-			Type       interface{} `json:"type"`
-			Offset     int         `json:"offset"`
-			Seniority  interface{} `json:"seniority"`
-			Department interface{} `json:"department"`
+		Pagination
+		Params struct {
+			Domain  string     `json:"domain"`
+			Company NullString `json:"company"`
+			Type    NullString `json:"type"`
+			// Offset is only sent back for some searches.
+			Offset     int        `json:"offset,omitempty"`
+			Seniority  StringList `json:"seniority"`
+			Department StringList `json:"department"`
 		} `json:"params"`
 	} `json:"meta"`
+	// Raw is the untouched response body the result was decoded from.
+	Raw json.RawMessage `json:"-"`
+	// Response describes the HTTP response the result was decoded from.
+	Response *ResponseMeta `json:"-"`
+}
+
+func (r *DomainSearchResult) setResponse(body []byte, meta *ResponseMeta) {
+	r.Raw = body
+	r.Response = meta
+}
+
+func (r *DomainSearchResult) response() ([]byte, *ResponseMeta) {
+	return r.Raw, r.Response
+}
+
+// credits estimates the credits used by the search, which
+// only counts when at least one email address is returned.
+func (r *DomainSearchResult) credits() float64 {
+	if len(r.Data.Emails) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// PatternExamples returns the email addresses found with the names of the
+// people they belong to, which pattern.Infer can use to infer the domain's
+// pattern when the API didn't return one, or to cross-check it.
+func (r *DomainSearchResult) PatternExamples() []pattern.Example {
+	var examples []pattern.Example
+	for _, email := range r.Data.Emails {
+		if email.Type == EmailTypeGeneric || (email.FirstName == "" && email.LastName == "") {
+			continue
+		}
+		examples = append(examples, pattern.Example{
+			FirstName: email.FirstName,
+			LastName:  email.LastName,
+			Email:     email.Value,
+		})
+	}
+	return examples
 }
 
 // DomainSearch searches a given domain. You give one domain name
 // and it returns all the email addresses using this domain name
 // found by https://hunter.io/ on the internet.
+//
+// The params are checked using ValidateDomainSearchParams before the request is sent.
 func (c *Client) DomainSearch(params Params) (*DomainSearchResult, error) {
-	body, err := c.request(context.Background(), http.MethodGet, "https://api.hunter.io/v2/domain-search", params)
-	if err != nil {
-		return nil, err
-	}
-	result := new(DomainSearchResult)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
+	return c.DomainSearchWithContext(context.Background(), params)
 }
 
 // DomainSearchWithContext searches a given domain. You give one domain name
 // and it returns all the email addresses using this domain name
 // found by https://hunter.io/ on the internet.
 //
+// The params are checked using ValidateDomainSearchParams before the request is sent.
+//
 // This context-based version of the function would be more suitable for long-running
 // applications like servers.
 func (c *Client) DomainSearchWithContext(ctx context.Context, params Params) (*DomainSearchResult, error) {
-	body, err := c.request(ctx, http.MethodGet, "https://api.hunter.io/v2/domain-search", params)
-	if err != nil {
+	if err := ValidateDomainSearchParams(params); err != nil {
 		return nil, err
 	}
 	result := new(DomainSearchResult)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
+	if err := c.call(ctx, "domain-search", params, result); err != nil {
 		return nil, err
 	}
 	return result, nil
@@ -2,8 +2,12 @@ package hunter
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/picatz/hunter/pattern"
 )
 
 func TestClient_DomainSearch(t *testing.T) {
@@ -27,3 +31,114 @@ func TestClient_DomainSearchWithContext(t *testing.T) {
 		t.Error("got not results")
 	}
 }
+
+func TestDomainSearchParams_Params(t *testing.T) {
+	p := &DomainSearchParams{
+		Domain:     "stripe.com",
+		Limit:      20,
+		Department: []string{DepartmentIT, DepartmentSales},
+		Seniority:  []string{SenioritySenior},
+	}
+	params := p.Params()
+	if params["department"] != "it,sales" {
+		t.Error("expected comma-delimited departments, got:", params["department"])
+	}
+	if params["limit"] != "20" {
+		t.Error("expected limit of 20, got:", params["limit"])
+	}
+	if _, ok := params["offset"]; ok {
+		t.Error("expected no offset, got:", params["offset"])
+	}
+	if err := ValidateDomainSearchParams(params); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateDomainSearchParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		valid  bool
+	}{
+		{"domain", Params{"domain": "stripe.com"}, true},
+		{"company", Params{"company": "stripe"}, true},
+		{"missing domain and company", Params{"type": "personal"}, false},
+		{"type", Params{"domain": "stripe.com", "type": "generic"}, true},
+		{"bad type", Params{"domain": "stripe.com", "type": "robot"}, false},
+		{"multiple types", Params{"domain": "stripe.com", "type": "generic,personal"}, false},
+		{"limit", Params{"domain": "stripe.com", "limit": "100"}, true},
+		{"limit too large", Params{"domain": "stripe.com", "limit": "101"}, false},
+		{"limit not a number", Params{"domain": "stripe.com", "limit": "ten"}, false},
+		{"negative offset", Params{"domain": "stripe.com", "offset": "-1"}, false},
+		{"departments", Params{"domain": "stripe.com", "department": "it, finance"}, true},
+		{"bad department", Params{"domain": "stripe.com", "department": "it,janitorial"}, false},
+		{"seniorities", Params{"domain": "stripe.com", "seniority": "junior,executive"}, true},
+		{"bad seniority", Params{"domain": "stripe.com", "seniority": "intern"}, false},
+		{"required fields", Params{"domain": "stripe.com", "required_field": "full_name,phone_number"}, true},
+		{"bad required field", Params{"domain": "stripe.com", "required_field": "twitter"}, false},
+		{"verification status", Params{"domain": "stripe.com", "verification_status": "valid,accept_all"}, true},
+		{"bad verification status", Params{"domain": "stripe.com", "verification_status": "invalid"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDomainSearchParams(test.params)
+			if test.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !test.valid && !errors.Is(err, ErrInvalidParams) {
+				t.Fatal("expected ErrInvalidParams, got:", err)
+			}
+		})
+	}
+}
+
+func TestDomainSearchResult_sharedTypes(t *testing.T) {
+	input := `{"data":{"domain":"stripe.com","emails":[{"value":"patrick@stripe.com","sources":[{"domain":"blog.stripe.com","uri":"http://blog.stripe.com","extracted_on":"2015-08-29","last_seen_on":"2017-02-13","still_on_page":true}]}]},"meta":{"results":35,"limit":10,"offset":0}}`
+	var result DomainSearchResult
+	if err := json.Unmarshal([]byte(input), &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Meta.HasMore() {
+		t.Error("expected more results after", result.Meta.Pagination)
+	}
+	firstSource := func(email DomainEmail) Source {
+		return email.Sources[0]
+	}
+	if source := firstSource(result.Data.Emails[0]); source.Domain != "blog.stripe.com" {
+		t.Error("unexpected source:", source)
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta struct {
+		Meta map[string]interface{} `json:"meta"`
+	}
+	if err := json.Unmarshal(b, &meta); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"results", "limit", "offset", "params"} {
+		if _, ok := meta.Meta[key]; !ok {
+			t.Errorf("expected %q in the encoded meta, got: %v", key, meta.Meta)
+		}
+	}
+}
+
+func TestDomainSearchResult_PatternExamples(t *testing.T) {
+	input := `{"data":{"domain":"asana.com","pattern":"{first}","emails":[{"value":"dustin@asana.com","type":"personal","first_name":"Dustin","last_name":"Moskovitz"},{"value":"info@asana.com","type":"generic"},{"value":"press@asana.com","type":"personal"}]}}`
+	var result DomainSearchResult
+	if err := json.Unmarshal([]byte(input), &result); err != nil {
+		t.Fatal(err)
+	}
+	examples := result.PatternExamples()
+	if len(examples) != 1 || examples[0].Email != "dustin@asana.com" || examples[0].LastName != "Moskovitz" {
+		t.Fatal("expected only the named personal email, got:", examples)
+	}
+	inference, err := pattern.Infer(examples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !inference.Agrees(result.Data.Pattern) {
+		t.Errorf("expected the inferred pattern %s to agree with %s", inference.Pattern, result.Data.Pattern)
+	}
+}
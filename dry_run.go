@@ -0,0 +1,63 @@
+package hunter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ErrDryRun is returned by every call made by a Client with DryRun set,
+// since the request is described instead of being sent.
+var ErrDryRun = errors.New("dry run, the request was not sent")
+
+// redacted replaces API keys in requests that are printed or logged.
+const redacted = "REDACTED"
+
+// redactURL returns the URL with the API key replaced.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	if q.Get("api_key") == "" {
+		return u.String()
+	}
+	q.Set("api_key", redacted)
+	r := *u
+	r.RawQuery = q.Encode()
+	return r.String()
+}
+
+// redactError replaces the API key in the URL of errors from the http.Client.
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, perr := url.Parse(urlErr.URL); perr == nil {
+			urlErr.URL = redactURL(u)
+		}
+	}
+	return err
+}
+
+// writeDryRun describes the request, with the API key redacted,
+// and the most credits it could use.
+func writeDryRun(w io.Writer, req *http.Request) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", req.Method, redactURL(req.URL))
+	var names []string
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s: %s\n", name, strings.Join(req.Header[name], ", "))
+	}
+	if key := req.URL.Query().Get("api_key"); key != "" {
+		fmt.Fprintf(&b, "Key-ID: %s\n", KeyID(key))
+	}
+	fmt.Fprintf(&b, "Estimated-Credits: %g\n\n", EstimatedCredits(path.Base(req.URL.Path)))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,48 @@
+package hunter
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_DryRun(t *testing.T) {
+	var out bytes.Buffer
+	client := New("secret-key", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			t.Fatal("expected no request to be sent")
+			return nil, nil
+		}),
+	})
+	client.DryRun = &out
+	_, err := client.DomainSearch(Params{"domain": "stripe.com"})
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatal("expected ErrDryRun, got:", err)
+	}
+	plan := out.String()
+	if strings.Contains(plan, "secret-key") {
+		t.Error("expected the API key to be redacted, got:", plan)
+	}
+	for _, want := range []string{"GET https://api.hunter.io/v2/domain-search?", "api_key=REDACTED", "domain=stripe.com", "Estimated-Credits: 1"} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("expected %q in the plan, got: %s", want, plan)
+		}
+	}
+}
+
+func TestClient_redactsErrors(t *testing.T) {
+	client := New("secret-key", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}),
+	})
+	_, err := client.Account()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(err.Error(), "secret-key") {
+		t.Error("expected the API key to be redacted, got:", err)
+	}
+}
@@ -1,12 +1,36 @@
 package hunter
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"net/http"
 )
 
+// EmailCounterParams is a typed form of the parameters accepted by the
+// CountEmails function. Use the Params method to get the Params to send.
+type EmailCounterParams struct {
+	Domain  string
+	Company string
+	Type    string
+}
+
+// Params returns the Params for the email count, omitting empty values.
+func (p *EmailCounterParams) Params() Params {
+	return Params{
+		"domain":  p.Domain,
+		"company": p.Company,
+		"type":    p.Type,
+	}
+}
+
+// emailCounterParams returns the typed form of the email count params.
+func emailCounterParams(params Params) *EmailCounterParams {
+	return &EmailCounterParams{
+		Domain:  params["domain"],
+		Company: params["company"],
+		Type:    params["type"],
+	}
+}
+
 // EmailCounterResult is returned by the CountEmails function.
 type EmailCounterResult struct {
 	Data struct {
@@ -33,24 +57,33 @@ type EmailCounterResult struct {
 	} `json:"data"`
 	Meta struct {
 		Params struct {
-			Domain string      `json:"domain"`
-			Type   interface{} `json:"type"`
+			Domain string     `json:"domain"`
+			Type   NullString `json:"type"`
 		} `json:"params"`
 	} `json:"meta"`
+	// Raw is the untouched response body the result was decoded from.
+	Raw json.RawMessage `json:"-"`
+	// Response describes the HTTP response the result was decoded from.
+	Response *ResponseMeta `json:"-"`
+}
+
+// credits returns zero, since counting emails is free.
+func (r *EmailCounterResult) credits() float64 {
+	return 0
+}
+
+func (r *EmailCounterResult) setResponse(body []byte, meta *ResponseMeta) {
+	r.Raw = body
+	r.Response = meta
+}
+
+func (r *EmailCounterResult) response() ([]byte, *ResponseMeta) {
+	return r.Raw, r.Response
 }
 
 // CountEmails  allows you to verify the deliverability of an email address.
 func (c *Client) CountEmails(params Params) (*EmailCounterResult, error) {
-	body, err := c.request(context.Background(), http.MethodGet, "https://api.hunter.io/v2/email-count", params)
-	if err != nil {
-		return nil, err
-	}
-	result := new(EmailCounterResult)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
+	return c.CountEmailsWithContext(context.Background(), params)
 }
 
 // CountEmailsWithContext allows you to verify the deliverability of an email address.
@@ -58,13 +91,8 @@ func (c *Client) CountEmails(params Params) (*EmailCounterResult, error) {
 // This context-based version of the function would be more suitable for long-running
 // applications like servers.
 func (c *Client) CountEmailsWithContext(ctx context.Context, params Params) (*EmailCounterResult, error) {
-	body, err := c.request(ctx, http.MethodGet, "https://api.hunter.io/v2/email-count", params)
-	if err != nil {
-		return nil, err
-	}
 	result := new(EmailCounterResult)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
+	if err := c.call(ctx, "email-count", params, result); err != nil {
 		return nil, err
 	}
 	return result, nil
@@ -1,72 +1,171 @@
 package hunter
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"net/http"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
+// EmailFinderParams is a typed form of the parameters accepted by the
+// FindEmail function. Use the Params method to get the Params to send.
+type EmailFinderParams struct {
+	Domain    string
+	Company   string
+	FirstName string
+	LastName  string
+	FullName  string
+	// LinkedinHandle identifies the person by their LinkedIn profile, and may
+	// be given as a handle like "dustinmoskovitz" or as a profile URL.
+	LinkedinHandle string
+	// MaxDuration is how many seconds the API may spend finding the email,
+	// between 3 and 20. Zero uses the API's default.
+	MaxDuration int
+}
+
+// Params returns the Params for the email finder, omitting empty values.
+func (p *EmailFinderParams) Params() Params {
+	params := Params{
+		"domain":          p.Domain,
+		"company":         p.Company,
+		"first_name":      p.FirstName,
+		"last_name":       p.LastName,
+		"full_name":       p.FullName,
+		"linkedin_handle": LinkedinHandle(p.LinkedinHandle),
+	}
+	if p.MaxDuration != 0 {
+		params["max_duration"] = strconv.Itoa(p.MaxDuration)
+	}
+	return params
+}
+
+// emailFinderParams returns the typed form of the email finder params.
+func emailFinderParams(params Params) *EmailFinderParams {
+	p := &EmailFinderParams{
+		Domain:         params["domain"],
+		Company:        params["company"],
+		FirstName:      params["first_name"],
+		LastName:       params["last_name"],
+		FullName:       params["full_name"],
+		LinkedinHandle: params["linkedin_handle"],
+	}
+	p.MaxDuration, _ = strconv.Atoi(params["max_duration"])
+	return p
+}
+
+// LinkedinHandle returns the LinkedIn handle from the given handle or
+// profile URL, like "https://www.linkedin.com/in/dustinmoskovitz/".
+// Values that don't look like a profile URL are returned trimmed.
+func LinkedinHandle(handleOrURL string) string {
+	handleOrURL = strings.TrimSpace(handleOrURL)
+	if !strings.Contains(handleOrURL, "linkedin.com/") {
+		return strings.Trim(handleOrURL, "/")
+	}
+	if !strings.Contains(handleOrURL, "://") {
+		handleOrURL = "https://" + handleOrURL
+	}
+	u, err := url.Parse(handleOrURL)
+	if err != nil {
+		return handleOrURL
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) >= 2 && parts[0] == "in" {
+		handle, err := url.PathUnescape(parts[1])
+		if err != nil {
+			return parts[1]
+		}
+		return handle
+	}
+	return handleOrURL
+}
+
+// ValidateEmailFinderParams checks the given email finder parameters locally,
+// returning an error wrapping ErrInvalidParams if they would be rejected.
+//
+// Either a domain or company is required, and the person must be identified
+// by a first and last name, a full name, or a LinkedIn handle.
+func ValidateEmailFinderParams(params Params) error {
+	if params["domain"] == "" && params["company"] == "" {
+		return fmt.Errorf("%w: either domain or company is required", ErrInvalidParams)
+	}
+	hasNames := params["first_name"] != "" && params["last_name"] != ""
+	if !hasNames && params["full_name"] == "" && params["linkedin_handle"] == "" {
+		return fmt.Errorf("%w: either first_name and last_name, full_name or linkedin_handle is required", ErrInvalidParams)
+	}
+	return checkInt(params, "max_duration", 3, 20)
+}
+
 // EmailFinderResult is returned by the FindEmail function.
 type EmailFinderResult struct {
 	Data struct {
-		FirstName   string      `json:"first_name"`
-		LastName    string      `json:"last_name"`
-		Email       string      `json:"email"`
-		Score       int         `json:"score"`
-		Domain      string      `json:"domain"`
-		Position    string      `json:"position"`
-		Twitter     string      `json:"twitter"`
-		LinkedinURL string      `json:"linkedin_url"`
-		PhoneNumber interface{} `json:"phone_number"`
-		Company     string      `json:"company"`
-		Sources     []struct {
-			Domain      string `json:"domain"`
-			URI         string `json:"uri"`
-			ExtractedOn string `json:"extracted_on"`
-			LastSeenOn  string `json:"last_seen_on"`
-			StillOnPage bool   `json:"still_on_page"`
-		} `json:"sources"`
+		FirstName   string     `json:"first_name"`
+		LastName    string     `json:"last_name"`
+		Email       string     `json:"email"`
+		Score       int        `json:"score"`
+		Domain      string     `json:"domain"`
+		Position    string     `json:"position"`
+		Twitter     string     `json:"twitter"`
+		LinkedinURL string     `json:"linkedin_url"`
+		PhoneNumber NullString `json:"phone_number"`
+		Company     string     `json:"company"`
+		Sources     []Source   `json:"sources"`
 	} `json:"data"`
 	Meta struct {
 		Params struct {
-			FirstName string      `json:"first_name"`
-			LastName  string      `json:"last_name"`
-			FullName  interface{} `json:"full_name"`
-			Domain    string      `json:"domain"`
-			Company   interface{} `json:"company"`
+			FirstName string     `json:"first_name"`
+			LastName  string     `json:"last_name"`
+			FullName  NullString `json:"full_name"`
+			Domain    string     `json:"domain"`
+			Company   NullString `json:"company"`
 		} `json:"params"`
 	} `json:"meta"`
+	// Raw is the untouched response body the result was decoded from.
+	Raw json.RawMessage `json:"-"`
+	// Response describes the HTTP response the result was decoded from.
+	Response *ResponseMeta `json:"-"`
+}
+
+func (r *EmailFinderResult) setResponse(body []byte, meta *ResponseMeta) {
+	r.Raw = body
+	r.Response = meta
+}
+
+func (r *EmailFinderResult) response() ([]byte, *ResponseMeta) {
+	return r.Raw, r.Response
+}
+
+// credits estimates the credits used by the email finder, which
+// only counts when an email address is found.
+func (r *EmailFinderResult) credits() float64 {
+	if r.Data.Email == "" {
+		return 0
+	}
+	return 1
 }
 
 // FindEmail generates or retrieves the most likely
 // email address from a domain name, a first name and a last name.
+//
+// The params are checked using ValidateEmailFinderParams before the request is sent.
 func (c *Client) FindEmail(params Params) (*EmailFinderResult, error) {
-	body, err := c.request(context.Background(), http.MethodGet, "https://api.hunter.io/v2/email-finder", params)
-	if err != nil {
-		return nil, err
-	}
-	result := new(EmailFinderResult)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
+	return c.FindEmailWithContext(context.Background(), params)
 }
 
 // FindEmailWithContext generates or retrieves the most likely
 // email address from a domain name, a first name and a last name.
 //
+// The params are checked using ValidateEmailFinderParams before the request is sent.
+//
 // This context-based version of the function would be more suitable for long-running
 // applications like servers.
 func (c *Client) FindEmailWithContext(ctx context.Context, params Params) (*EmailFinderResult, error) {
-	body, err := c.request(ctx, http.MethodGet, "https://api.hunter.io/v2/email-finder", params)
-	if err != nil {
+	if err := ValidateEmailFinderParams(params); err != nil {
 		return nil, err
 	}
 	result := new(EmailFinderResult)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
+	if err := c.call(ctx, "email-finder", params, result); err != nil {
 		return nil, err
 	}
 	return result, nil
@@ -2,6 +2,7 @@ package hunter
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -35,3 +36,47 @@ func TestClient_FindEmailWithContext(t *testing.T) {
 		t.Error("unable to find known email", results)
 	}
 }
+
+func TestLinkedinHandle(t *testing.T) {
+	tests := []struct {
+		input, handle string
+	}{
+		{"dustinmoskovitz", "dustinmoskovitz"},
+		{" dustinmoskovitz/ ", "dustinmoskovitz"},
+		{"https://www.linkedin.com/in/dustinmoskovitz/", "dustinmoskovitz"},
+		{"linkedin.com/in/dustinmoskovitz?trk=nav", "dustinmoskovitz"},
+		{"https://linkedin.com/in/j%C3%BCrgen-m", "jürgen-m"},
+	}
+	for _, test := range tests {
+		if handle := LinkedinHandle(test.input); handle != test.handle {
+			t.Errorf("LinkedinHandle(%q) = %q, want %q", test.input, handle, test.handle)
+		}
+	}
+}
+
+func TestValidateEmailFinderParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *EmailFinderParams
+		valid  bool
+	}{
+		{"names", &EmailFinderParams{Domain: "asana.com", FirstName: "Dustin", LastName: "Moskovitz"}, true},
+		{"full name", &EmailFinderParams{Company: "Asana", FullName: "Dustin Moskovitz"}, true},
+		{"linkedin handle", &EmailFinderParams{Domain: "asana.com", LinkedinHandle: "https://www.linkedin.com/in/dustinmoskovitz"}, true},
+		{"missing domain and company", &EmailFinderParams{FullName: "Dustin Moskovitz"}, false},
+		{"only first name", &EmailFinderParams{Domain: "asana.com", FirstName: "Dustin"}, false},
+		{"max duration", &EmailFinderParams{Domain: "asana.com", FullName: "Dustin Moskovitz", MaxDuration: 20}, true},
+		{"max duration too short", &EmailFinderParams{Domain: "asana.com", FullName: "Dustin Moskovitz", MaxDuration: 2}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateEmailFinderParams(test.params.Params())
+			if test.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !test.valid && !errors.Is(err, ErrInvalidParams) {
+				t.Fatal("expected ErrInvalidParams, got:", err)
+			}
+		})
+	}
+}
@@ -1,54 +1,90 @@
 package hunter
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"net/http"
 )
 
+// EmailVerifierParams is a typed form of the parameters accepted by the
+// VerifyEmail function. Use the Params method to get the Params to send.
+type EmailVerifierParams struct {
+	Email string
+}
+
+// Params returns the Params for the email verification.
+func (p *EmailVerifierParams) Params() Params {
+	return Params{"email": p.Email}
+}
+
+// emailVerifierParams returns the typed form of the email verification params.
+func emailVerifierParams(params Params) *EmailVerifierParams {
+	return &EmailVerifierParams{Email: params["email"]}
+}
+
 // EmailVerifierResult is returned by the VerifyEmail function.
 type EmailVerifierResult struct {
 	Data struct {
-		Result     string `json:"result"`
-		Score      int    `json:"score"`
-		Email      string `json:"email"`
-		Regexp     bool   `json:"regexp"`
-		Gibberish  bool   `json:"gibberish"`
-		Disposable bool   `json:"disposable"`
-		Webmail    bool   `json:"webmail"`
-		MxRecords  bool   `json:"mx_records"`
-		SMTPServer bool   `json:"smtp_server"`
-		SMTPCheck  bool   `json:"smtp_check"`
-		AcceptAll  bool   `json:"accept_all"`
-		Block      bool   `json:"block"`
-		Sources    []struct {
-			Domain      string `json:"domain"`
-			URI         string `json:"uri"`
-			ExtractedOn string `json:"extracted_on"`
-			LastSeenOn  string `json:"last_seen_on"`
-			StillOnPage bool   `json:"still_on_page"`
-		} `json:"sources"`
+		Status     VerificationStatus `json:"status,omitempty"`
+		Result     string             `json:"result"`
+		Score      int                `json:"score"`
+		Email      string             `json:"email"`
+		Regexp     bool               `json:"regexp"`
+		Gibberish  bool               `json:"gibberish"`
+		Disposable bool               `json:"disposable"`
+		Webmail    bool               `json:"webmail"`
+		MxRecords  bool               `json:"mx_records"`
+		SMTPServer bool               `json:"smtp_server"`
+		SMTPCheck  bool               `json:"smtp_check"`
+		AcceptAll  bool               `json:"accept_all"`
+		Block      bool               `json:"block"`
+		Sources    []Source           `json:"sources"`
 	} `json:"data"`
 	Meta struct {
 		Params struct {
 			Email string `json:"email"`
 		} `json:"params"`
 	} `json:"meta"`
+	// Raw is the untouched response body the result was decoded from.
+	Raw json.RawMessage `json:"-"`
+	// Response describes the HTTP response the result was decoded from.
+	Response *ResponseMeta `json:"-"`
+}
+
+func (r *EmailVerifierResult) setResponse(body []byte, meta *ResponseMeta) {
+	r.Raw = body
+	r.Response = meta
+}
+
+func (r *EmailVerifierResult) response() ([]byte, *ResponseMeta) {
+	return r.Raw, r.Response
+}
+
+// Legacy verification results, found in EmailVerifierResult.Data.Result.
+// Newer responses also include the more detailed EmailVerifierResult.Data.Status.
+const (
+	VerificationResultDeliverable   = "deliverable"
+	VerificationResultUndeliverable = "undeliverable"
+	VerificationResultRisky         = "risky"
+)
+
+// IsSafeToSend returns true if the email address was verified as valid.
+// The legacy result is used if the API didn't send a status.
+func (r *EmailVerifierResult) IsSafeToSend() bool {
+	if r.Data.Status != "" {
+		return r.Data.Status.IsSafeToSend()
+	}
+	return r.Data.Result == VerificationResultDeliverable
+}
+
+// credits estimates the credits used by the verification,
+// where each credit covers two verifications.
+func (r *EmailVerifierResult) credits() float64 {
+	return 0.5
 }
 
 // VerifyEmail  allows you to verify the deliverability of an email address.
 func (c *Client) VerifyEmail(params Params) (*EmailVerifierResult, error) {
-	body, err := c.request(context.Background(), http.MethodGet, "https://api.hunter.io/v2/email-verifier", params)
-	if err != nil {
-		return nil, err
-	}
-	result := new(EmailVerifierResult)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
+	return c.VerifyEmailWithContext(context.Background(), params)
 }
 
 // VerifyEmailWithContext allows you to verify the deliverability of an email address.
@@ -56,13 +92,8 @@ func (c *Client) VerifyEmail(params Params) (*EmailVerifierResult, error) {
 // This context-based version of the function would be more suitable for long-running
 // applications like servers.
 func (c *Client) VerifyEmailWithContext(ctx context.Context, params Params) (*EmailVerifierResult, error) {
-	body, err := c.request(ctx, http.MethodGet, "https://api.hunter.io/v2/email-verifier", params)
-	if err != nil {
-		return nil, err
-	}
 	result := new(EmailVerifierResult)
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
-	if err != nil {
+	if err := c.call(ctx, "email-verifier", params, result); err != nil {
 		return nil, err
 	}
 	return result, nil
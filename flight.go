@@ -0,0 +1,118 @@
+package hunter
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// flightGroup collapses identical calls which are in flight at the same
+// time into one, like many goroutines searching the same domain at once.
+type flightGroup struct {
+	mu        sync.Mutex
+	flights   map[string]*flight
+	collapsed int64
+	// joined is called, if set, once a call is collapsed into one in
+	// flight, so tests can wait for it
+	joined func()
+}
+
+// flight is a call shared by the callers waiting for it.
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	result  result
+	err     error
+}
+
+// flightKey identifies calls to the URL with the same key and params.
+func flightKey(url, key string, params Params) string {
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(url + "\x00" + key)
+	for _, k := range keys {
+		b.WriteString("\x00" + k + "=" + params[k])
+	}
+	return b.String()
+}
+
+// do runs fn once for every call with the same key that is in flight, and
+// copies the decoded result of the shared call into each caller's result.
+//
+// The shared call runs with a context holding the values of the first
+// caller's context, which is only canceled once every caller waiting for it
+// has given up, so one caller's cancellation doesn't fail the others.
+func (g *flightGroup) do(ctx context.Context, key string, res result, fn func(context.Context, result) error) error {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+	f, ok := g.flights[key]
+	if ok {
+		g.collapsed++
+	} else {
+		fctx, cancel := context.WithCancel(detachedContext{ctx})
+		f = &flight{
+			done:   make(chan struct{}),
+			cancel: cancel,
+			result: reflect.New(reflect.TypeOf(res).Elem()).Interface().(result),
+		}
+		g.flights[key] = f
+		go func() {
+			err := fn(fctx, f.result)
+			g.mu.Lock()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+			f.err = err
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	joined := g.joined
+	g.mu.Unlock()
+	if ok && joined != nil {
+		joined()
+	}
+
+	select {
+	case <-f.done:
+		reflect.ValueOf(res).Elem().Set(reflect.ValueOf(f.result).Elem())
+		return f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// nobody is left to use the result, so the call is canceled,
+			// and a later identical call starts a new one
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			f.cancel()
+		}
+		g.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// detachedContext has the values of its parent context,
+// but neither its deadline nor its cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
@@ -0,0 +1,152 @@
+package hunter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newBlockingClient returns a client whose requests wait for release to be
+// closed, counting the requests sent and signaling each one on started, and
+// each one canceled on canceled.
+func newBlockingClient(requests *int32, started, canceled chan<- struct{}, release <-chan struct{}) *Client {
+	return New("test-key", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(requests, 1)
+			started <- struct{}{}
+			select {
+			case <-release:
+			case <-req.Context().Done():
+				canceled <- struct{}{}
+				return nil, req.Context().Err()
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"data":{"domain":"stripe.com","emails":[{"value":"patrick@stripe.com"}]}}`)),
+				Request:    req,
+			}, nil
+		}),
+	})
+}
+
+func TestClient_collapsesIdenticalCalls(t *testing.T) {
+	var requests int32
+	started, release := make(chan struct{}, 10), make(chan struct{})
+	canceled := make(chan struct{}, 10)
+	client := newBlockingClient(&requests, started, canceled, release)
+	joined := make(chan struct{}, 10)
+	client.flights.joined = func() { joined <- struct{}{} }
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make([]*DomainSearchResult, callers)
+	errs := make([]error, callers)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], errs[0] = client.DomainSearch(Params{"domain": "stripe.com"})
+	}()
+	<-started
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// the same domain once normalized
+			results[i], errs[i] = client.DomainSearch(Params{"domain": "https://www.stripe.com/"})
+		}(i)
+	}
+	// wait for every caller to join the call in flight
+	for i := 1; i < callers; i++ {
+		<-joined
+	}
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if len(results[i].Data.Emails) != 1 || results[i].Data.Emails[0].Value != "patrick@stripe.com" {
+			t.Errorf("expected the shared result, got: %+v", results[i].Data)
+		}
+	}
+	// each caller has its own metadata, with the domain it gave
+	if results[0].Response.OriginalDomain != "stripe.com" || results[1].Response.OriginalDomain != "https://www.stripe.com/" {
+		t.Errorf("expected each caller's own domain, got %q and %q", results[0].Response.OriginalDomain, results[1].Response.OriginalDomain)
+	}
+	if results[0].Response == results[1].Response || results[1].Response.Domain != "stripe.com" {
+		t.Errorf("expected a copy of the metadata for each caller, got: %+v", results[1].Response)
+	}
+
+	// calls which aren't in flight at the same time aren't collapsed
+	if _, err := client.DomainSearch(Params{"domain": "stripe.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || client.Collapsed() != callers-1 {
+		t.Errorf("expected 2 requests and %d collapsed calls, got %d and %d", callers-1, requests, client.Collapsed())
+	}
+}
+
+func TestClient_collapsedCallCancellation(t *testing.T) {
+	var requests int32
+	started, release := make(chan struct{}, 10), make(chan struct{})
+	canceled := make(chan struct{}, 10)
+	client := newBlockingClient(&requests, started, canceled, release)
+	joined := make(chan struct{}, 10)
+	client.flights.joined = func() { joined <- struct{}{} }
+
+	// a caller which joined the call in flight giving up
+	// doesn't cancel the call for the caller which started it
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := client.VerifyEmail(Params{"email": "patrick@stripe.com"})
+		first <- err
+	}()
+	<-started
+	second := make(chan error)
+	go func() {
+		_, err := client.VerifyEmailWithContext(ctx, Params{"email": "patrick@stripe.com"})
+		second <- err
+	}()
+	<-joined
+	cancel()
+	if err := <-second; !errors.Is(err, context.Canceled) {
+		t.Error("expected the canceled caller to get context.Canceled, got:", err)
+	}
+	close(release)
+	if err := <-first; err != nil {
+		t.Error("expected the other caller to get the result, got:", err)
+	}
+	if len(canceled) != 0 {
+		t.Error("expected the shared request not to be canceled")
+	}
+
+	// the call is canceled once every caller has given up
+	client = newBlockingClient(&requests, started, canceled, make(chan struct{}))
+	ctx, cancel = context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := client.CountEmailsWithContext(ctx, Params{"domain": "stripe.com"})
+		done <- err
+	}()
+	<-started
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Error("expected context.Canceled, got:", err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Error("expected the shared request to be canceled")
+	}
+}
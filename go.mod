@@ -2,4 +2,8 @@ module github.com/picatz/hunter
 
 go 1.13
 
-require github.com/spf13/cobra v1.7.0
+require (
+	github.com/spf13/cobra v1.7.0
+	golang.org/x/net v0.11.0
+	golang.org/x/text v0.13.0
+)
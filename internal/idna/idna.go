@@ -0,0 +1,29 @@
+// Package idna converts internationalized domain names to their ASCII
+// form, using the lookup profile of UTS #46 from golang.org/x/net/idna.
+package idna
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalid is returned when a domain name can't be converted.
+var ErrInvalid = errors.New("invalid internationalized domain name")
+
+// ToASCII maps the domain as it would be looked up, normalizing it to NFC,
+// lowercasing it and mapping compatibility characters like fullwidth letters
+// and ideographic full stops, then converts each label containing non-ASCII
+// characters to its Punycode form, like "bücher.example" to
+// "xn--bcher-kva.example".
+func ToASCII(domain string) (string, error) {
+	if !utf8.ValidString(domain) {
+		return "", ErrInvalid
+	}
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	return ascii, nil
+}
@@ -0,0 +1,32 @@
+package idna
+
+import "testing"
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		domain string
+		ascii  string
+	}{
+		{"example.com", "example.com"},
+		{"Example.COM", "example.com"},
+		{"bücher.example", "xn--bcher-kva.example"},
+		{"München.de", "xn--mnchen-3ya.de"},
+		{"пример.рф", "xn--e1afmkfd.xn--p1ai"},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+		{"ñ。com", "xn--ida.com"},
+		{"mu\u0308nchen.de", "xn--mnchen-3ya.de"},
+		{"ＥＸＡＭＰＬＥ.com", "example.com"},
+	}
+	for _, test := range tests {
+		ascii, err := ToASCII(test.domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ascii != test.ascii {
+			t.Errorf("expected %s to give %s, got: %s", test.domain, test.ascii, ascii)
+		}
+	}
+	if _, err := ToASCII("\xff.com"); err != ErrInvalid {
+		t.Error("expected invalid UTF-8 to be rejected, got:", err)
+	}
+}
@@ -0,0 +1,195 @@
+package hunter
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoKeyAvailable is returned when every key in a KeyPool is unusable,
+// because they were rejected, are rate limited, or have no calls left.
+var ErrNoKeyAvailable = errors.New("no API key in the pool is available")
+
+// Ways a KeyPool can select the key for each call.
+const (
+	// SelectLeastUsed selects the key which has made the fewest calls.
+	SelectLeastUsed = "least-used"
+	// SelectWeighted selects a random key, in proportion to its weight.
+	SelectWeighted = "weighted"
+)
+
+// rateLimitCooldown is how long a rate limited key is skipped for
+// when the API didn't say when the rate limit resets.
+const rateLimitCooldown = time.Minute
+
+// PoolKey is an API key in a KeyPool, and what is known about its usage.
+type PoolKey struct {
+	Key string
+	// Weight is the key's share of calls using SelectWeighted, defaulting to 1.
+	Weight int
+	// Used and Available are the calls used and available in the current
+	// period, as of the last refresh, plus the calls made since then.
+	// Available is -1 if it is unknown.
+	Used      int
+	Available int
+
+	rejected bool
+	limited  time.Time
+}
+
+// ID returns a redacted form of the key, safe to print or log.
+func (k *PoolKey) ID() string {
+	return KeyID(k.Key)
+}
+
+// KeyID returns a redacted form of an API key, safe to print or log,
+// made of its last four characters.
+func KeyID(key string) string {
+	if len(key) <= 4 {
+		return strings.Repeat("*", len(key))
+	}
+	return "..." + key[len(key)-4:]
+}
+
+// KeyPool spreads a Client's calls across the API keys of several accounts.
+// When a key is rejected or rate limited, the call is retried with another.
+type KeyPool struct {
+	// Strategy is either SelectLeastUsed (the default) or SelectWeighted.
+	Strategy string
+
+	mu   sync.Mutex
+	keys []*PoolKey
+	rand *rand.Rand
+}
+
+// NewKeyPool returns a KeyPool using the given keys. Each key may be followed
+// by a colon and its weight, like "key:3".
+func NewKeyPool(keys ...string) *KeyPool {
+	p := &KeyPool{rand: rand.New(rand.NewSource(now().UnixNano()))}
+	for _, key := range keys {
+		weight := 1
+		if i := strings.LastIndex(key, ":"); i > 0 {
+			if w, err := strconv.Atoi(key[i+1:]); err == nil && w > 0 {
+				key, weight = key[:i], w
+			}
+		}
+		p.keys = append(p.keys, &PoolKey{Key: key, Weight: weight, Available: -1})
+	}
+	return p
+}
+
+// Keys returns a copy of the keys in the pool, with their usage.
+func (p *KeyPool) Keys() []PoolKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	keys := make([]PoolKey, len(p.keys))
+	for i, k := range p.keys {
+		keys[i] = *k
+	}
+	return keys
+}
+
+// Refresh updates the calls used and available for each key using the Account
+// endpoint, which is free, with the given client's HTTP client.
+func (p *KeyPool) Refresh(ctx context.Context, client *Client) error {
+	p.mu.Lock()
+	keys := append([]*PoolKey(nil), p.keys...)
+	p.mu.Unlock()
+	// the refresh calls aren't recorded as the client's own calls
+	refreshClient := client.Clone()
+	refreshClient.Ledger = nil
+	var errs []string
+	for _, k := range keys {
+		account, err := refreshClient.WithKey(k.Key).AccountWithContext(ctx)
+		p.mu.Lock()
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			k.rejected = true
+		case err != nil:
+			errs = append(errs, k.ID()+": "+err.Error())
+		default:
+			k.Used = account.Data.Calls.Used
+			k.Available = account.Data.Calls.Available
+		}
+		p.mu.Unlock()
+	}
+	if len(errs) > 0 {
+		return errors.New("hunter: refreshing key pool: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// next selects the key for a call, skipping the keys already tried.
+func (p *KeyPool) next(tried map[*PoolKey]bool) (*PoolKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var candidates []*PoolKey
+	for _, k := range p.keys {
+		if tried[k] || k.rejected || now().Before(k.limited) {
+			continue
+		}
+		if k.Available >= 0 && k.Used >= k.Available {
+			continue
+		}
+		candidates = append(candidates, k)
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNoKeyAvailable
+	}
+	if p.Strategy == SelectWeighted {
+		total := 0
+		for _, k := range candidates {
+			total += keyWeight(k)
+		}
+		n := p.rand.Intn(total)
+		for _, k := range candidates {
+			if n -= keyWeight(k); n < 0 {
+				return k, nil
+			}
+		}
+	}
+	least := candidates[0]
+	for _, k := range candidates[1:] {
+		if k.Used < least.Used {
+			least = k
+		}
+	}
+	return least, nil
+}
+
+// done records the outcome of a call made with the key, so rejected
+// and rate limited keys are skipped.
+func (p *KeyPool) done(k *PoolKey, meta *ResponseMeta, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		k.rejected = true
+	case errors.Is(err, ErrTooManyRequests):
+		k.limited = now().Add(rateLimitCooldown)
+		if meta != nil && meta.RateLimitReset.After(now()) {
+			k.limited = meta.RateLimitReset
+		}
+	default:
+		if meta != nil && meta.Credits > 0 {
+			k.Used++
+		}
+	}
+}
+
+// retryWithNextKey returns true if a call failing with the error
+// should be retried with another key from the pool.
+func retryWithNextKey(err error) bool {
+	return errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrTooManyRequests)
+}
+
+func keyWeight(k *PoolKey) int {
+	if k.Weight <= 0 {
+		return 1
+	}
+	return k.Weight
+}
@@ -0,0 +1,116 @@
+package hunter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newKeyPoolTestClient returns a client whose requests get the
+// response status for the key they were sent with.
+func newKeyPoolTestClient(statuses map[string]int, body string) *Client {
+	return New("", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			status, ok := statuses[req.URL.Query().Get("api_key")]
+			if !ok {
+				status = 200
+			}
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	})
+}
+
+func TestClient_KeyPool(t *testing.T) {
+	client := newKeyPoolTestClient(map[string]int{"key-limited": 429, "key-revoked": 401}, `{"data":{"emails":[{"value":"patrick@stripe.com"}]}}`)
+	client.KeyPool = NewKeyPool("key-limited", "key-revoked", "key-good1", "key-good2")
+	used := map[string]int{}
+	for i := 0; i < 4; i++ {
+		result, err := client.DomainSearch(Params{"domain": "stripe.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		used[result.Response.KeyID]++
+	}
+	if used[KeyID("key-good1")] != 2 || used[KeyID("key-good2")] != 2 {
+		t.Errorf("expected the calls to be spread across the good keys, got: %v", used)
+	}
+	for _, k := range client.KeyPool.Keys() {
+		if (k.Key == "key-good1" || k.Key == "key-good2") && k.Used != 2 {
+			t.Errorf("expected %s to have been used twice, got: %d", k.ID(), k.Used)
+		}
+	}
+}
+
+func TestClient_KeyPoolExhausted(t *testing.T) {
+	client := newKeyPoolTestClient(map[string]int{"key-a": 429, "key-b": 429}, `{}`)
+	client.KeyPool = NewKeyPool("key-a", "key-b")
+	if _, err := client.Account(); !errors.Is(err, ErrTooManyRequests) {
+		t.Fatal("expected ErrTooManyRequests, got:", err)
+	}
+	if _, err := client.Account(); err != ErrNoKeyAvailable {
+		t.Fatal("expected ErrNoKeyAvailable while the keys are rate limited, got:", err)
+	}
+}
+
+func TestKeyPool_Refresh(t *testing.T) {
+	client := newKeyPoolTestClient(map[string]int{"key-revoked": 401}, `{"data":{"calls":{"used":25,"available":25}}}`)
+	pool := NewKeyPool("key-full", "key-revoked")
+	if err := pool.Refresh(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	keys := pool.Keys()
+	if keys[0].Used != 25 || keys[0].Available != 25 {
+		t.Errorf("expected the key's usage to be refreshed, got: %+v", keys[0])
+	}
+	if _, err := pool.next(nil); err != ErrNoKeyAvailable {
+		t.Fatal("expected no key with calls left, got:", err)
+	}
+}
+
+func TestKeyPool_RefreshBaseURL(t *testing.T) {
+	var hosts []string
+	client := New("", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			hosts = append(hosts, req.URL.Host)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"data":{"calls":{"used":1,"available":25}}}`)),
+				Request:    req,
+			}, nil
+		}),
+	})
+	client.BaseURL = "http://localhost:8080/v2"
+	if err := NewKeyPool("key-a").Refresh(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 1 || hosts[0] != "localhost:8080" {
+		t.Error("expected the keys to be refreshed against the base URL, got:", hosts)
+	}
+}
+
+func TestKeyPool_weighted(t *testing.T) {
+	pool := NewKeyPool("key-heavy:9", "key-light")
+	pool.Strategy = SelectWeighted
+	if keys := pool.Keys(); keys[0].Key != "key-heavy" || keys[0].Weight != 9 {
+		t.Fatalf("expected the weight to be parsed, got: %+v", keys[0])
+	}
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		k, err := pool.next(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[k.Key]++
+	}
+	if counts["key-heavy"] < 800 || counts["key-light"] == 0 {
+		t.Errorf("expected the calls to be weighted 9:1, got: %v", counts)
+	}
+}
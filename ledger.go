@@ -0,0 +1,225 @@
+package hunter
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LedgerEntry is a call recorded in a Ledger.
+type LedgerEntry struct {
+	Time     time.Time `json:"time"`
+	Endpoint string    `json:"endpoint"`
+	// ParamsHash identifies the params of the call, ignoring order,
+	// case and surrounding whitespace, without storing them.
+	ParamsHash string  `json:"params_hash"`
+	Domain     string  `json:"domain,omitempty"`
+	Credits    float64 `json:"credits"`
+	Profile    string  `json:"profile,omitempty"`
+	Tag        string  `json:"tag,omitempty"`
+	Status     int     `json:"status"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Ledger records calls made by a Client as lines of JSON in a local file,
+// so usage can be attributed to scripts, people, or domains.
+type Ledger struct {
+	// Path is the file entries are appended to.
+	Path string
+	// Profile and Tag are recorded with every entry, to tell apart the
+	// accounts and scripts sharing a ledger.
+	Profile string
+	Tag     string
+
+	mu sync.Mutex
+}
+
+// NewLedger returns a Ledger which appends to the file at the given path.
+func NewLedger(path string) *Ledger {
+	return &Ledger{Path: path}
+}
+
+// DefaultLedgerPath returns the default ledger file path, which
+// follows the XDG base directory specification for data files.
+func DefaultLedgerPath() string {
+	return filepath.Join(dataDir(), "ledger.jsonl")
+}
+
+// dataDir returns the directory used to store hunter's local data.
+func dataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "hunter")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "hunter"
+	}
+	return filepath.Join(home, ".local", "share", "hunter")
+}
+
+// Record appends the entry to the ledger, filling in its profile and tag.
+func (l *Ledger) Record(entry LedgerEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if entry.Profile == "" {
+		entry.Profile = l.Profile
+	}
+	if entry.Tag == "" {
+		entry.Tag = l.Tag
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Entries returns all the entries in the ledger. A missing ledger file has no entries.
+func (l *Ledger) Entries() ([]LedgerEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.Open(l.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadLedger(f)
+}
+
+// ReadLedger reads ledger entries from lines of JSON.
+func ReadLedger(r io.Reader) ([]LedgerEntry, error) {
+	var entries []LedgerEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry LedgerEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// newLedgerEntry returns the ledger entry for a call with the given
+// response metadata, params and error.
+func newLedgerEntry(meta *ResponseMeta, params Params, err error) LedgerEntry {
+	entry := LedgerEntry{
+		Time:       now().UTC(),
+		Endpoint:   meta.Endpoint,
+		ParamsHash: hashParams(params),
+		Domain:     paramsDomain(params),
+		Credits:    meta.Credits,
+		Status:     meta.StatusCode,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return entry
+}
+
+// hashParams returns a short hash of the normalized, non-empty params.
+func hashParams(params Params) string {
+	var pairs []string
+	for k, v := range params {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v != "" {
+			pairs = append(pairs, k+"="+v)
+		}
+	}
+	sort.Strings(pairs)
+	sum := sha256.Sum256([]byte(strings.Join(pairs, "&")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// paramsDomain returns the domain the params refer to, if any.
+func paramsDomain(params Params) string {
+	if domain := params["domain"]; domain != "" {
+		return strings.ToLower(domain)
+	}
+	if i := strings.LastIndex(params["email"], "@"); i >= 0 {
+		return strings.ToLower(params["email"][i+1:])
+	}
+	return ""
+}
+
+// UsageSummary is the usage recorded in a ledger for one group of entries.
+type UsageSummary struct {
+	Key     string  `json:"key"`
+	Calls   int     `json:"calls"`
+	Credits float64 `json:"credits"`
+	Errors  int     `json:"errors"`
+}
+
+// Ways ledger entries can be grouped by SummarizeUsage.
+const (
+	UsageByDay      = "day"
+	UsageByEndpoint = "endpoint"
+	UsageByDomain   = "domain"
+	UsageByTag      = "tag"
+	UsageByProfile  = "profile"
+)
+
+// SummarizeUsage groups the entries by day, endpoint, domain, tag or
+// profile, returning the summaries sorted by key.
+func SummarizeUsage(entries []LedgerEntry, by string) []UsageSummary {
+	groups := map[string]*UsageSummary{}
+	var keys []string
+	for _, entry := range entries {
+		var key string
+		switch by {
+		case UsageByEndpoint:
+			key = entry.Endpoint
+		case UsageByDomain:
+			key = entry.Domain
+		case UsageByTag:
+			key = entry.Tag
+		case UsageByProfile:
+			key = entry.Profile
+		default:
+			key = entry.Time.Format(DateFormat)
+		}
+		summary, ok := groups[key]
+		if !ok {
+			summary = &UsageSummary{Key: key}
+			groups[key] = summary
+			keys = append(keys, key)
+		}
+		summary.Calls++
+		summary.Credits += entry.Credits
+		if entry.Error != "" {
+			summary.Errors++
+		}
+	}
+	sort.Strings(keys)
+	summaries := make([]UsageSummary, 0, len(keys))
+	for _, key := range keys {
+		summaries = append(summaries, *groups[key])
+	}
+	return summaries
+}
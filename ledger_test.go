@@ -0,0 +1,104 @@
+package hunter
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_Ledger(t *testing.T) {
+	ledger := NewLedger(filepath.Join(t.TempDir(), "hunter", "ledger.jsonl"))
+	ledger.Tag = "enrichment"
+	client := newTestClient(200, `{"data":{"emails":[{"value":"patrick@stripe.com"}]}}`)
+	client.Ledger = ledger
+	if _, err := client.DomainSearch(Params{"domain": "Stripe.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DomainSearch(Params{"domain": " stripe.com", "type": ""}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ledger.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatal("expected 2 ledger entries, got:", len(entries))
+	}
+	entry := entries[0]
+	if entry.Endpoint != "domain-search" || entry.Domain != "stripe.com" || entry.Credits != 1 || entry.Status != 200 || entry.Tag != "enrichment" {
+		t.Errorf("unexpected ledger entry: %+v", entry)
+	}
+	if entries[0].ParamsHash != entries[1].ParamsHash {
+		t.Error("expected normalized params to have the same hash")
+	}
+}
+
+func TestClient_LedgerErrors(t *testing.T) {
+	ledger := NewLedger(filepath.Join(t.TempDir(), "ledger.jsonl"))
+	client := newTestClient(429, `{}`)
+	client.Ledger = ledger
+	if _, err := client.VerifyEmail(Params{"email": "steli@close.io"}); !errors.Is(err, ErrTooManyRequests) {
+		t.Fatal("expected ErrTooManyRequests, got:", err)
+	}
+	entries, err := ledger.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Status != 429 || entries[0].Error == "" || entries[0].Domain != "close.io" {
+		t.Errorf("unexpected ledger entries: %+v", entries)
+	}
+}
+
+func TestClient_LedgerWriteError(t *testing.T) {
+	// the ledger's directory is a file, so it can't be written
+	dir := filepath.Join(t.TempDir(), "file")
+	if err := ioutil.WriteFile(dir, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	var recordErrs []error
+	client := newTestClient(200, `{"data":{"email":"patrick@stripe.com","score":91}}`)
+	client.Ledger = NewLedger(filepath.Join(dir, "ledger.jsonl"))
+	client.OnRecordError = func(err error) {
+		recordErrs = append(recordErrs, err)
+	}
+	result, err := client.FindEmail(Params{"domain": "stripe.com", "full_name": "Patrick Collison"})
+	if err != nil {
+		t.Fatal("expected the call to succeed, got:", err)
+	}
+	if result.Data.Email != "patrick@stripe.com" {
+		t.Error("expected the result to be kept, got:", result.Data.Email)
+	}
+	if len(recordErrs) != 1 {
+		t.Error("expected the ledger error to be reported, got:", recordErrs)
+	}
+}
+
+func TestSummarizeUsage(t *testing.T) {
+	entries, err := ReadLedger(strings.NewReader(`
+{"time":"2019-01-01T10:00:00Z","endpoint":"domain-search","domain":"stripe.com","credits":1,"tag":"a","status":200}
+{"time":"2019-01-01T11:00:00Z","endpoint":"email-verifier","domain":"close.io","credits":0.5,"tag":"b","status":200}
+{"time":"2019-01-02T09:00:00Z","endpoint":"domain-search","domain":"stripe.com","credits":0,"tag":"a","status":429,"error":"rate limited"}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		by   string
+		want []UsageSummary
+	}{
+		{UsageByDay, []UsageSummary{{"2019-01-01", 2, 1.5, 0}, {"2019-01-02", 1, 0, 1}}},
+		{UsageByEndpoint, []UsageSummary{{"domain-search", 2, 1, 1}, {"email-verifier", 1, 0.5, 0}}},
+		{UsageByDomain, []UsageSummary{{"close.io", 1, 0.5, 0}, {"stripe.com", 2, 1, 1}}},
+		{UsageByTag, []UsageSummary{{"a", 2, 1, 1}, {"b", 1, 0.5, 0}}},
+	}
+	for _, test := range tests {
+		t.Run(test.by, func(t *testing.T) {
+			if got := SummarizeUsage(entries, test.by); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package hunter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Call is a call to an endpoint, as seen by Middleware.
+type Call struct {
+	// Endpoint is the name of the endpoint, like "domain-search".
+	Endpoint string
+	// Params are the params sent, after their domain is normalized.
+	// They should not be modified.
+	Params Params
+	// Request is the typed form of the params: a *DomainSearchParams,
+	// *EmailFinderParams, *EmailCounterParams or *EmailVerifierParams,
+	// or nil for the account endpoint. Modifying it has no effect.
+	Request interface{}
+	// Result is the decoded result once the call succeeded, like a
+	// *DomainSearchResult, and nil otherwise. It is shared with the
+	// caller, so it should not be modified.
+	Result interface{}
+	// Header holds headers added to the request, which
+	// middleware may set, like for tracing.
+	Header http.Header
+}
+
+// newCall returns the call to the endpoint with the given normalized params.
+func newCall(endpoint string, params Params) *Call {
+	call := &Call{Endpoint: endpoint, Params: params, Header: http.Header{}}
+	switch endpoint {
+	case "domain-search":
+		call.Request = domainSearchParams(params)
+	case "email-finder":
+		call.Request = emailFinderParams(params)
+	case "email-count":
+		call.Request = emailCounterParams(params)
+	case "email-verifier":
+		call.Request = emailVerifierParams(params)
+	}
+	return call
+}
+
+// Handler makes a call, returning the metadata of its response, which is nil
+// if no response was received, and its error, if any.
+type Handler func(ctx context.Context, call *Call) (*ResponseMeta, error)
+
+// Middleware wraps a Handler to do something around every call a Client
+// makes, like logging it, or changing its headers before calling next.
+//
+// Calls collapsed into an identical call in flight only go through the
+// middleware once, and calls with an invalid param don't go through it.
+type Middleware func(next Handler) Handler
+
+// chain returns the handler wrapped by the middleware, the first of which
+// is the outermost.
+func chain(middleware []Middleware, handler Handler) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// Logger is where LogMiddleware logs calls, with alternating keys and values
+// like the Info and Error methods of *slog.Logger, which implements it.
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// LogMiddleware logs every call with its endpoint, params, duration, status,
+// request ID, key ID and credits, and its error if it failed. API keys are
+// never logged, only their key IDs, since they aren't part of the params.
+// Calls of a dry run are logged with a dry_run attribute, and not as failed.
+func LogMiddleware(logger Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*ResponseMeta, error) {
+			start := now()
+			meta, err := next(ctx, call)
+			args := []interface{}{
+				"endpoint", call.Endpoint,
+				"params", formatParams(call.Params),
+				"duration", now().Sub(start),
+			}
+			if meta != nil {
+				args = append(args,
+					"status", meta.StatusCode,
+					"request_id", meta.RequestID,
+					"key_id", meta.KeyID,
+					"credits", meta.Credits,
+				)
+			}
+			switch {
+			case errors.Is(err, ErrDryRun):
+				logger.Info("hunter call", append(args, "dry_run", true)...)
+			case err != nil:
+				logger.Error("hunter call failed", append(args, "error", err.Error())...)
+			default:
+				logger.Info("hunter call", args...)
+			}
+			return meta, err
+		}
+	}
+}
+
+// formatParams formats the params sorted by name, like "domain=stripe.com&limit=10".
+func formatParams(params Params) string {
+	var pairs []string
+	for k, v := range params {
+		if v == "" {
+			continue
+		}
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// DefaultLatencyBuckets are the upper bounds of the latency histograms
+// of Metrics without buckets of their own.
+var DefaultLatencyBuckets = []time.Duration{
+	50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond,
+	500 * time.Millisecond, time.Second, 2500 * time.Millisecond,
+	5 * time.Second, 10 * time.Second,
+}
+
+// Histogram counts durations in buckets.
+type Histogram struct {
+	// Buckets are the upper bounds of the buckets, in increasing order.
+	Buckets []time.Duration `json:"buckets"`
+	// Counts are the number of durations in each bucket, with a
+	// last count for those above every bucket.
+	Counts []int64       `json:"counts"`
+	Count  int64         `json:"count"`
+	Sum    time.Duration `json:"sum"`
+}
+
+// Observe adds the duration to its bucket.
+func (h *Histogram) Observe(d time.Duration) {
+	if len(h.Counts) != len(h.Buckets)+1 {
+		h.Counts = make([]int64, len(h.Buckets)+1)
+	}
+	i := 0
+	for i < len(h.Buckets) && d > h.Buckets[i] {
+		i++
+	}
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+}
+
+// Mean returns the mean of the durations, or zero if there are none.
+func (h *Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// EndpointMetrics are the metrics of the calls to an endpoint.
+type EndpointMetrics struct {
+	Calls int64 `json:"calls"`
+	// Errors counts the failed calls, not counting those of a dry run.
+	Errors int64 `json:"errors"`
+	// Statuses counts the calls by status code, with calls
+	// which didn't receive a response counted as 0.
+	Statuses map[int]int64 `json:"statuses"`
+	Credits  float64       `json:"credits"`
+	Latency  Histogram     `json:"latency"`
+}
+
+// Metrics counts calls and measures their latency by endpoint, using its
+// Middleware. It is safe for concurrent use, and its zero value is ready
+// to use.
+type Metrics struct {
+	// Buckets are the upper bounds of the latency histograms, which
+	// are DefaultLatencyBuckets if nil.
+	Buckets []time.Duration
+
+	mu        sync.Mutex
+	endpoints map[string]*EndpointMetrics
+}
+
+// Middleware returns the middleware recording the metrics of every call.
+func (m *Metrics) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*ResponseMeta, error) {
+			start := now()
+			meta, err := next(ctx, call)
+			m.record(call.Endpoint, meta, err, now().Sub(start))
+			return meta, err
+		}
+	}
+}
+
+func (m *Metrics) record(endpoint string, meta *ResponseMeta, err error, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.endpoints == nil {
+		m.endpoints = map[string]*EndpointMetrics{}
+	}
+	e, ok := m.endpoints[endpoint]
+	if !ok {
+		buckets := m.Buckets
+		if buckets == nil {
+			buckets = DefaultLatencyBuckets
+		}
+		e = &EndpointMetrics{Statuses: map[int]int64{}, Latency: Histogram{Buckets: buckets}}
+		m.endpoints[endpoint] = e
+	}
+	e.Calls++
+	if err != nil && !errors.Is(err, ErrDryRun) {
+		e.Errors++
+	}
+	var status int
+	if meta != nil {
+		status = meta.StatusCode
+		e.Credits += meta.Credits
+	}
+	e.Statuses[status]++
+	e.Latency.Observe(d)
+}
+
+// Endpoints returns a copy of the metrics of each endpoint called so far.
+func (m *Metrics) Endpoints() map[string]EndpointMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	endpoints := make(map[string]EndpointMetrics, len(m.endpoints))
+	for name, e := range m.endpoints {
+		c := *e
+		c.Statuses = make(map[int]int64, len(e.Statuses))
+		for status, n := range e.Statuses {
+			c.Statuses[status] = n
+		}
+		c.Latency.Counts = append([]int64(nil), e.Latency.Counts...)
+		endpoints[name] = c
+	}
+	return endpoints
+}
@@ -0,0 +1,168 @@
+package hunter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testLogger records the lines logged by LogMiddleware.
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Info(msg string, args ...interface{}) {
+	l.lines = append(l.lines, "INFO "+msg+" "+strings.TrimSpace(fmt.Sprintln(args...)))
+}
+
+func (l *testLogger) Error(msg string, args ...interface{}) {
+	l.lines = append(l.lines, "ERROR "+msg+" "+strings.TrimSpace(fmt.Sprintln(args...)))
+}
+
+func TestClient_Middleware(t *testing.T) {
+	var headers []string
+	client := New("secret-test-key", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			headers = append(headers, req.Header.Get("Traceparent"))
+			status := 200
+			if req.URL.Query().Get("domain") == "" {
+				status = 401
+			}
+			return &http.Response{
+				StatusCode: status,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"data":{"domain":"stripe.com","emails":[{"value":"patrick@stripe.com"}]}}`)),
+				Request:    req,
+			}, nil
+		}),
+	})
+
+	var order []string
+	named := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) (*ResponseMeta, error) {
+				order = append(order, name+" "+call.Endpoint+" "+call.Params["domain"])
+				call.Header.Set("Traceparent", "00-trace-"+name)
+				meta, err := next(ctx, call)
+				order = append(order, name+" done")
+				return meta, err
+			}
+		}
+	}
+	logger := &testLogger{}
+	metrics := &Metrics{Buckets: []time.Duration{time.Second}}
+	client.Middleware = []Middleware{named("outer"), named("inner"), LogMiddleware(logger), metrics.Middleware()}
+
+	if _, err := client.DomainSearch(Params{"domain": "https://stripe.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.VerifyEmail(Params{"email": "patrick@stripe.com"}); !errors.Is(err, ErrUnauthorized) {
+		t.Fatal("expected ErrUnauthorized, got:", err)
+	}
+
+	expected := "outer domain-search stripe.com,inner domain-search stripe.com,inner done,outer done"
+	if got := strings.Join(order[:4], ","); got != expected {
+		t.Errorf("expected the middleware to run in order %q, got %q", expected, got)
+	}
+	if headers[0] != "00-trace-inner" {
+		t.Error("expected the header set by the middleware to be sent, got:", headers[0])
+	}
+
+	if len(logger.lines) != 2 {
+		t.Fatalf("expected 2 lines logged, got: %q", logger.lines)
+	}
+	for _, line := range logger.lines {
+		if strings.Contains(line, "secret-test-key") {
+			t.Error("expected the key to be redacted, got:", line)
+		}
+		if !strings.Contains(line, KeyID("secret-test-key")) {
+			t.Error("expected the key ID to be logged, got:", line)
+		}
+	}
+	if !strings.HasPrefix(logger.lines[0], "INFO hunter call endpoint domain-search params domain=stripe.com") {
+		t.Error("unexpected log line:", logger.lines[0])
+	}
+	if !strings.HasPrefix(logger.lines[1], "ERROR hunter call failed endpoint email-verifier") || !strings.Contains(logger.lines[1], "status 401") {
+		t.Error("unexpected log line:", logger.lines[1])
+	}
+
+	endpoints := metrics.Endpoints()
+	search, verify := endpoints["domain-search"], endpoints["email-verifier"]
+	if search.Calls != 1 || search.Errors != 0 || search.Statuses[200] != 1 || search.Credits != 1 {
+		t.Errorf("unexpected domain search metrics: %+v", search)
+	}
+	if verify.Calls != 1 || verify.Errors != 1 || verify.Statuses[401] != 1 {
+		t.Errorf("unexpected email verifier metrics: %+v", verify)
+	}
+	if search.Latency.Count != 1 || len(search.Latency.Counts) != 2 {
+		t.Errorf("unexpected latency histogram: %+v", search.Latency)
+	}
+}
+
+func TestLogMiddleware_dryRun(t *testing.T) {
+	client := newTestClient(200, `{}`)
+	client.DryRun = ioutil.Discard
+	logger := &testLogger{}
+	metrics := &Metrics{}
+	client.Middleware = []Middleware{LogMiddleware(logger), metrics.Middleware()}
+	if _, err := client.DomainSearch(Params{"domain": "stripe.com"}); !errors.Is(err, ErrDryRun) {
+		t.Fatal("expected ErrDryRun, got:", err)
+	}
+	if len(logger.lines) != 1 || !strings.HasPrefix(logger.lines[0], "INFO hunter call") || !strings.HasSuffix(logger.lines[0], "dry_run true") {
+		t.Errorf("expected the dry run to be logged as a call, got: %q", logger.lines)
+	}
+	if search := metrics.Endpoints()["domain-search"]; search.Calls != 1 || search.Errors != 0 {
+		t.Errorf("expected the dry run not to be counted as an error, got: %+v", search)
+	}
+}
+
+func TestClient_MiddlewareTypedCall(t *testing.T) {
+	client := newTestClient(200, `{"data":{"domain":"stripe.com","emails":[{"value":"patrick@stripe.com"}]}}`)
+	var calls []*Call
+	client.Middleware = []Middleware{func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*ResponseMeta, error) {
+			meta, err := next(ctx, call)
+			calls = append(calls, call)
+			return meta, err
+		}
+	}}
+	params := (&DomainSearchParams{Domain: "https://www.stripe.com", Limit: 10, Department: []string{DepartmentIT, DepartmentSales}}).Params()
+	if _, err := client.DomainSearch(params); err != nil {
+		t.Fatal(err)
+	}
+	request, ok := calls[0].Request.(*DomainSearchParams)
+	if !ok || request.Domain != "stripe.com" || request.Limit != 10 || len(request.Department) != 2 {
+		t.Errorf("expected the typed params, got: %+v", calls[0].Request)
+	}
+	result, ok := calls[0].Result.(*DomainSearchResult)
+	if !ok || len(result.Data.Emails) != 1 {
+		t.Errorf("expected the decoded result, got: %+v", calls[0].Result)
+	}
+
+	if _, err := client.VerifyEmail((&EmailVerifierParams{Email: "patrick@stripe.com"}).Params()); err != nil {
+		t.Fatal(err)
+	}
+	if request, ok := calls[1].Request.(*EmailVerifierParams); !ok || request.Email != "patrick@stripe.com" {
+		t.Errorf("expected the typed params, got: %+v", calls[1].Request)
+	}
+	if _, ok := calls[1].Result.(*EmailVerifierResult); !ok {
+		t.Errorf("expected the decoded result, got: %T", calls[1].Result)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := Histogram{Buckets: []time.Duration{100 * time.Millisecond, time.Second}}
+	for _, d := range []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 500 * time.Millisecond, 2 * time.Second} {
+		h.Observe(d)
+	}
+	if fmt.Sprint(h.Counts) != "[2 1 1]" {
+		t.Error("expected counts of [2 1 1], got:", h.Counts)
+	}
+	if h.Count != 4 || h.Mean() != 662500*time.Microsecond {
+		t.Errorf("expected 4 durations with a mean of 662.5ms, got %d and %s", h.Count, h.Mean())
+	}
+}
@@ -0,0 +1,143 @@
+// Package name parses people's full names into their first, middle and last
+// names, to send them separately to the Email Finder, which gives better
+// results than a full name.
+package name
+
+import (
+	"strings"
+)
+
+// Name is a person's name split into its parts.
+type Name struct {
+	// Title is a title before the name, like "Dr.".
+	Title  string `json:"title,omitempty"`
+	First  string `json:"first"`
+	Middle string `json:"middle,omitempty"`
+	// Last includes its particles, like "van der Berg".
+	Last string `json:"last"`
+	// Suffix is a suffix after the name, like "Jr." or "PhD".
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// String returns the name in order, without its title or suffix.
+func (n Name) String() string {
+	return strings.Join(strings.Fields(n.First+" "+n.Middle+" "+n.Last), " ")
+}
+
+// Parse splits the full name into its parts, after normalizing it. The name
+// may be reversed with a comma, like "Berg, Anna van der". Particles like
+// "van der" are kept in the last name, and titles and suffixes are set apart.
+// A name of a single word is only a first name.
+func Parse(full string) Name {
+	var n Name
+	full = Normalize(full)
+	if i := strings.IndexByte(full, ','); i >= 0 {
+		before, after := strings.TrimSpace(full[:i]), strings.TrimSpace(full[i+1:])
+		if allSuffixes(strings.Fields(after)) {
+			// "John Smith, Jr."
+			full = before + " " + after
+		} else {
+			// "Smith, John" or "Smith Jr., John"
+			words := strings.Fields(before)
+			words, n.Suffix = splitSuffixes(words)
+			rest := strings.Fields(after)
+			var suffix string
+			rest, suffix = splitSuffixes(rest)
+			n.Suffix = strings.TrimSpace(n.Suffix + " " + suffix)
+			rest, n.Title = splitTitles(rest)
+			// particles after the given name, like "Berg, Anna van der",
+			// belong to the last name
+			end := len(rest)
+			for end > 1 && IsParticle(rest[end-1]) {
+				end--
+			}
+			words = append(append([]string(nil), rest[end:]...), words...)
+			rest = rest[:end]
+			n.Last = strings.Join(words, " ")
+			if len(rest) > 0 {
+				n.First = rest[0]
+				n.Middle = strings.Join(rest[1:], " ")
+			}
+			return n
+		}
+	}
+	words := strings.Fields(strings.Replace(full, ",", " ", -1))
+	words, n.Suffix = splitSuffixes(words)
+	words, n.Title = splitTitles(words)
+	switch len(words) {
+	case 0:
+		return n
+	case 1:
+		n.First = words[0]
+		return n
+	}
+	// the last name starts at the last word, or the
+	// particles before it, keeping at least a first name
+	start := len(words) - 1
+	for start > 1 && IsParticle(words[start-1]) {
+		start--
+	}
+	n.First = words[0]
+	n.Middle = strings.Join(words[1:start], " ")
+	n.Last = strings.Join(words[start:], " ")
+	return n
+}
+
+// particles are the lowercase words before family names, like "van der".
+var particles = map[string]bool{
+	"al": true, "bin": true, "da": true, "das": true, "de": true, "del": true,
+	"della": true, "den": true, "der": true, "di": true, "do": true, "dos": true,
+	"du": true, "el": true, "la": true, "le": true, "st": true, "ten": true,
+	"ter": true, "van": true, "von": true,
+}
+
+// IsParticle returns true if the word is a particle of family names,
+// like "van" or "de", which are often left out of email addresses.
+func IsParticle(word string) bool {
+	return particles[strings.ToLower(word)]
+}
+
+// suffixes are the lowercase suffixes after names, without periods.
+var suffixes = map[string]bool{
+	"jr": true, "sr": true, "ii": true, "iii": true, "iv": true, "phd": true,
+	"md": true, "esq": true, "dds": true, "mba": true, "cpa": true,
+}
+
+// titles are the lowercase titles before names, without periods.
+var titles = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "mx": true, "dr": true,
+	"prof": true, "sir": true, "dame": true,
+}
+
+func isSuffix(word string) bool {
+	return suffixes[strings.ToLower(strings.Trim(word, ".,"))]
+}
+
+func allSuffixes(words []string) bool {
+	for _, word := range words {
+		if !isSuffix(word) {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// splitSuffixes removes the suffixes at the end of the words,
+// keeping at least one word.
+func splitSuffixes(words []string) ([]string, string) {
+	end := len(words)
+	for end > 1 && isSuffix(words[end-1]) {
+		end--
+	}
+	return words[:end], strings.Join(words[end:], " ")
+}
+
+// splitTitles removes the titles at the start of the words,
+// keeping at least one word.
+func splitTitles(words []string) ([]string, string) {
+	start := 0
+	for start < len(words)-1 && titles[strings.ToLower(strings.Trim(words[start], "."))] {
+		start++
+	}
+	return words[start:], strings.Join(words[:start], " ")
+}
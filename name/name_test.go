@@ -0,0 +1,83 @@
+package name
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		full string
+		name Name
+	}{
+		{"Dustin Moskovitz", Name{First: "Dustin", Last: "Moskovitz"}},
+		{"  dustin   MOSKOVITZ ", Name{First: "Dustin", Last: "Moskovitz"}},
+		{"John Fitzgerald Kennedy", Name{First: "John", Middle: "Fitzgerald", Last: "Kennedy"}},
+		{"Ludwig van Beethoven", Name{First: "Ludwig", Last: "van Beethoven"}},
+		{"Anna Maria van der Berg", Name{First: "Anna", Middle: "Maria", Last: "van der Berg"}},
+		{"Maria de la Cruz", Name{First: "Maria", Last: "de la Cruz"}},
+		{"Martin Luther King Jr.", Name{First: "Martin", Middle: "Luther", Last: "King", Suffix: "Jr."}},
+		{"John Smith, Jr.", Name{First: "John", Last: "Smith", Suffix: "Jr."}},
+		{"Henry Ford III", Name{First: "Henry", Last: "Ford", Suffix: "III"}},
+		{"Dr. Jane Doe PhD", Name{Title: "Dr.", First: "Jane", Last: "Doe", Suffix: "PhD"}},
+		{"Smith, John", Name{First: "John", Last: "Smith"}},
+		{"van der Berg, Anna Maria", Name{First: "Anna", Middle: "Maria", Last: "van der Berg"}},
+		{"Berg, Anna van der", Name{First: "Anna", Last: "van der Berg"}},
+		{"BERG, ANNA VAN DER", Name{First: "Anna", Last: "van der Berg"}},
+		{"Van Morrison", Name{First: "Van", Last: "Morrison"}},
+		{"Smith Jr., John", Name{First: "John", Last: "Smith", Suffix: "Jr."}},
+		{"o'brien, conan", Name{First: "Conan", Last: "O'Brien"}},
+		{"Jean-luc PICARD", Name{First: "Jean-luc", Last: "Picard"}},
+		{"Cher", Name{First: "Cher"}},
+		{"Mr. Bean", Name{Title: "Mr.", First: "Bean"}},
+		{"José García", Name{First: "José", Last: "García"}},
+		{"", Name{}},
+	}
+	for _, test := range tests {
+		if name := Parse(test.full); name != test.name {
+			t.Errorf("expected %q to give %+v, got: %+v", test.full, test.name, name)
+		}
+	}
+}
+
+func TestName_String(t *testing.T) {
+	if s := Parse("Dr. Anna Maria van der Berg, PhD").String(); s != "Anna Maria van der Berg" {
+		t.Error("unexpected name:", s)
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	tests := map[string]string{
+		"Björn Strauß":    "Bjorn Strauss",
+		"ÉMILE Zoë":       "EMILE Zoe",
+		"Łukasz Żółć":     "Lukasz Zolc",
+		"O’Connor":        "O'Connor",
+		"José":           "Jose",
+		"Ærøskøbing":      "Aeroskobing",
+		"Пётр Chaikovsky": "Chaikovsky",
+		"Nguyễn Trần":     "Nguyen Tran",
+		"Đặng Thị":        "Dang Thi",
+		"ＪＯＨＮ":            "JOHN",
+		"Ştefan Dvořák":   "Stefan Dvorak",
+		"Jo\u0301zsef":    "Jozsef",
+		"Renée Lévesque":  "Renee Levesque",
+	}
+	for name, expected := range tests {
+		if s := Transliterate(name); s != expected {
+			t.Errorf("expected %q to give %q, got: %q", name, expected, s)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"José  GARCÍA":    "José García",
+		"anna VAN DER berg": "Anna van der Berg",
+		"JD SALINGER":       "JD Salinger",
+		"McDonald":          "McDonald",
+		"Jose\u0301 ＭＡＲＩＡ":  "José Maria",
+		"nguyễn văn ANH":    "Nguyễn Văn Anh",
+	}
+	for name, expected := range tests {
+		if s := Normalize(name); s != expected {
+			t.Errorf("expected %q to give %q, got: %q", name, expected, s)
+		}
+	}
+}
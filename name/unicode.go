@@ -0,0 +1,91 @@
+package name
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize normalizes the name to NFKC, composing the letters written with
+// combining marks, like "e" followed by U+0301, into a single letter like
+// "é", and replacing compatibility characters like fullwidth letters, and
+// collapses its whitespace. Words written entirely in upper or lower case,
+// like "JOHN" or "o'brien", are capitalized, except for suffixes like "III"
+// and particles like "van", which are lowercased.
+func Normalize(name string) string {
+	words := strings.Fields(norm.NFKC.String(name))
+	for i, word := range words {
+		if isSuffix(word) {
+			continue
+		}
+		oneCase := word == strings.ToUpper(word) || word == strings.ToLower(word)
+		if particles[strings.ToLower(word)] && len(words) > 1 {
+			// particles written like "Van" are kept, since
+			// they may be part of a given name
+			if oneCase {
+				words[i] = strings.ToLower(word)
+			}
+			continue
+		}
+		if word == strings.ToUpper(word) && len([]rune(word)) > 2 || word == strings.ToLower(word) {
+			words[i] = capitalize(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalize lowercases the word, and uppercases its first letter and
+// those after a hyphen or apostrophe, like "O'Brien" or "Smith-Jones".
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	upper := true
+	for i, r := range runes {
+		if upper && unicode.IsLetter(r) {
+			runes[i] = unicode.ToUpper(r)
+			upper = false
+		}
+		if r == '-' || r == '\'' || r == '’' {
+			upper = true
+		}
+	}
+	return string(runes)
+}
+
+// Transliterate returns the ASCII form of the name, like "Bjorn Strauss" for
+// "Björn Strauß", keeping its case. The name is decomposed to NFKD, removing
+// the diacritics of its letters, and the letters which don't decompose, like
+// "ł" or "ß", are replaced. Apostrophes are kept, and letters which can't be
+// transliterated, like those of non-Latin scripts, are removed, collapsing
+// the whitespace left.
+func Transliterate(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(name) {
+		switch {
+		case r < unicode.MaxASCII:
+			b.WriteRune(r)
+		case r == '’':
+			b.WriteRune('\'')
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		default:
+			s, ok := transliterations[unicode.ToLower(r)]
+			if !ok {
+				continue
+			}
+			if unicode.IsUpper(r) {
+				s = strings.ToUpper(s[:1]) + s[1:]
+			}
+			b.WriteString(s)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// transliterations are the ASCII forms of the lowercase
+// Latin letters which don't decompose into a letter and its
+// diacritics.
+var transliterations = map[rune]string{
+	'æ': "ae", 'đ': "d", 'ð': "d", 'ı': "i", 'ł': "l",
+	'ø': "o", 'œ': "oe", 'ß': "ss", 'þ': "th",
+}
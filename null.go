@@ -0,0 +1,84 @@
+package hunter
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+var jsonNull = []byte("null")
+
+// NullString is a string returned by the API which may be null.
+// Valid is false when the value was null or missing.
+type NullString struct {
+	String string
+	Valid  bool
+	// Raw is the JSON of a value which was neither a string nor null, like
+	// an array or an object, which is kept instead of failing to decode the
+	// whole result. Valid is false in that case.
+	Raw string
+}
+
+// UnmarshalJSON decodes a JSON string or null, keeping any other value in Raw.
+func (n *NullString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, jsonNull) {
+		*n = NullString{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		*n = NullString{Raw: string(data)}
+		return nil
+	}
+	*n = NullString{String: s, Valid: true}
+	return nil
+}
+
+// MarshalJSON encodes the string, the raw value if any, or null.
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if n.Raw != "" {
+		return []byte(n.Raw), nil
+	}
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.String)
+}
+
+// StringList is a list of strings returned by the API, which may be sent
+// as null, a single comma-delimited string, or an array of strings. Other
+// values, and elements of the array which aren't strings, are kept as their
+// JSON instead of failing to decode the whole result.
+type StringList []string
+
+// UnmarshalJSON decodes a JSON array, a comma-delimited string, or null.
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, jsonNull) {
+		*l = nil
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*l = splitList(s)
+		return nil
+	}
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		*l = StringList{string(data)}
+		return nil
+	}
+	list := make(StringList, 0, len(elements))
+	for _, element := range elements {
+		var s string
+		if err := json.Unmarshal(element, &s); err != nil {
+			s = string(bytes.TrimSpace(element))
+		}
+		list = append(list, s)
+	}
+	*l = list
+	return nil
+}
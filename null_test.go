@@ -0,0 +1,133 @@
+package hunter
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNullString_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  NullString
+		err   bool
+	}{
+		{`null`, NullString{}, false},
+		{`""`, NullString{String: "", Valid: true}, false},
+		{`"+1 415 555 0100"`, NullString{String: "+1 415 555 0100", Valid: true}, false},
+		{`42`, NullString{Raw: `42`}, false},
+		{`["a"]`, NullString{Raw: `["a"]`}, false},
+		{`{"url":"x"}`, NullString{Raw: `{"url":"x"}`}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			var n NullString
+			err := json.Unmarshal([]byte(test.input), &n)
+			if test.err {
+				if err == nil {
+					t.Fatal("expected an error, got:", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != test.want {
+				t.Errorf("got %#v, want %#v", n, test.want)
+			}
+			b, err := json.Marshal(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != test.input {
+				t.Errorf("marshaled to %s, want %s", b, test.input)
+			}
+		})
+	}
+}
+
+func TestStringList_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  StringList
+		err   bool
+	}{
+		{`null`, nil, false},
+		{`"senior"`, StringList{"senior"}, false},
+		{`"it,finance"`, StringList{"it", "finance"}, false},
+		{`""`, nil, false},
+		{`["junior","executive"]`, StringList{"junior", "executive"}, false},
+		{`[]`, StringList{}, false},
+		{`7`, StringList{"7"}, false},
+		{`[1,"it",{"a":1}]`, StringList{"1", "it", `{"a":1}`}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			var l StringList
+			err := json.Unmarshal([]byte(test.input), &l)
+			if test.err {
+				if err == nil {
+					t.Fatal("expected an error, got:", l)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(l, test.want) {
+				t.Errorf("got %#v, want %#v", l, test.want)
+			}
+		})
+	}
+}
+
+func TestDomainSearchResult_nullableFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		phone     NullString
+		company   NullString
+		seniority StringList
+	}{
+		{
+			name:  "nulls",
+			input: `{"data":{"emails":[{"phone_number":null,"linkedin":null}]},"meta":{"params":{"company":null,"seniority":null}}}`,
+		},
+		{
+			name:      "strings",
+			input:     `{"data":{"emails":[{"phone_number":"+1 415 555 0100","linkedin":"https://linkedin.com/in/x"}]},"meta":{"params":{"company":"Stripe","seniority":"senior,executive"}}}`,
+			phone:     NullString{String: "+1 415 555 0100", Valid: true},
+			company:   NullString{String: "Stripe", Valid: true},
+			seniority: StringList{"senior", "executive"},
+		},
+		{
+			name:      "arrays",
+			input:     `{"data":{"emails":[{}]},"meta":{"params":{"seniority":["junior"]}}}`,
+			seniority: StringList{"junior"},
+		},
+		{
+			name:      "unexpected",
+			input:     `{"data":{"emails":[{"phone_number":["+1 415 555 0100"]}]},"meta":{"params":{"company":{"name":"Stripe"},"seniority":["junior"]}}}`,
+			phone:     NullString{Raw: `["+1 415 555 0100"]`},
+			company:   NullString{Raw: `{"name":"Stripe"}`},
+			seniority: StringList{"junior"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var result DomainSearchResult
+			if err := json.Unmarshal([]byte(test.input), &result); err != nil {
+				t.Fatal(err)
+			}
+			if got := result.Data.Emails[0].PhoneNumber; got != test.phone {
+				t.Errorf("phone number: got %#v, want %#v", got, test.phone)
+			}
+			if got := result.Meta.Params.Company; got != test.company {
+				t.Errorf("company: got %#v, want %#v", got, test.company)
+			}
+			if got := result.Meta.Params.Seniority; !reflect.DeepEqual(got, test.seniority) {
+				t.Errorf("seniority: got %#v, want %#v", got, test.seniority)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package hunter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// NewClient returns a client configured by the options. Without a key
+// option, the HUNTER_API_KEY environment variable is used, and without
+// an HTTP client option, UseDefaultHTTPClient is used.
+//
+//	client := hunter.NewClient(
+//		hunter.WithAPIKey(key),
+//		hunter.WithTimeout(10*time.Second),
+//		hunter.WithUserAgent("enrichment/1.0"),
+//	)
+func NewClient(opts ...Option) *Client {
+	c := &Client{Key: os.Getenv("HUNTER_API_KEY"), client: UseDefaultHTTPClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.timeout > 0 {
+		// copied to leave the timeout of a shared client, like
+		// http.DefaultClient, untouched
+		client := *c.client
+		client.Timeout = c.timeout
+		c.client = &client
+	}
+	return c
+}
+
+// WithAPIKey sets the API key used by the client, replacing any key provider.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.Key, c.keyProvider = key, nil
+	}
+}
+
+// WithKeyProvider makes the client get the API key of each call from the
+// provider, like from a secrets manager or from the context of a request
+// made on behalf of a customer. Calls fail with its error, if any.
+func WithKeyProvider(provider func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) {
+		c.keyProvider = provider
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests, or
+// UseDefaultHTTPClient if nil.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		if client == nil {
+			client = UseDefaultHTTPClient
+		}
+		c.client = client
+	}
+}
+
+// WithTimeout sets the time limit of each request, including reading the
+// response body, without changing the HTTP client given by WithHTTPClient.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithBaseURL sets the URL the API endpoints are relative to.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithMiddleware adds middleware wrapping every call the client makes.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
+// WithStrict makes calls fail with an error wrapping ErrUnknownField when
+// a response contains a field the result type doesn't model.
+func WithStrict(strict bool) Option {
+	return func(c *Client) {
+		c.Strict = strict
+	}
+}
+
+// WithRegistrableDomain makes domain params be reduced to the
+// domain which can be registered, using RegistrableDomain.
+func WithRegistrableDomain(registrable bool) Option {
+	return func(c *Client) {
+		c.RegistrableDomain = registrable
+	}
+}
+
+// WithLedger records every call the client makes in the ledger.
+func WithLedger(ledger *Ledger) Option {
+	return func(c *Client) {
+		c.Ledger = ledger
+	}
+}
+
+// WithOnRecordError sets the function called with the errors
+// recording a call in the ledger or the budget.
+func WithOnRecordError(fn func(err error)) Option {
+	return func(c *Client) {
+		c.OnRecordError = fn
+	}
+}
+
+// WithBudget limits the credits the client may spend.
+func WithBudget(budget *Budget) Option {
+	return func(c *Client) {
+		c.Budget = budget
+	}
+}
+
+// WithDryRun makes every call describe the request it would
+// send to the writer instead of sending it.
+func WithDryRun(w io.Writer) Option {
+	return func(c *Client) {
+		c.DryRun = w
+	}
+}
+
+// WithKeyPool spreads calls across the keys of the pool,
+// which is used instead of the API key.
+func WithKeyPool(pool *KeyPool) Option {
+	return func(c *Client) {
+		c.KeyPool = pool
+	}
+}
+
+// SetKey changes the API key used by later calls, replacing any key
+// provider. Unlike setting the Key field, it is safe to call while
+// other goroutines are making calls.
+func (c *Client) SetKey(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Key, c.keyProvider = key, nil
+}
+
+// Clone returns a copy of the client, whose fields can be changed without
+// affecting the client. The ledger, budget and key pool are shared, and
+// calls aren't collapsed with the client's calls.
+func (c *Client) Clone() *Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return &Client{
+		Key:               c.Key,
+		BaseURL:           c.BaseURL,
+		Strict:            c.Strict,
+		Ledger:            c.Ledger,
+		OnRecordError:     c.OnRecordError,
+		Budget:            c.Budget,
+		DryRun:            c.DryRun,
+		KeyPool:           c.KeyPool,
+		RegistrableDomain: c.RegistrableDomain,
+		Middleware:        append([]Middleware(nil), c.Middleware...),
+		client:            c.client,
+		keyProvider:       c.keyProvider,
+		timeout:           c.timeout,
+		userAgent:         c.userAgent,
+	}
+}
+
+// WithKey returns a copy of the client which uses the given API key
+// instead of its key, key provider or key pool, like to make calls on
+// behalf of a customer with their own key.
+func (c *Client) WithKey(key string) *Client {
+	clone := c.Clone()
+	clone.Key, clone.keyProvider, clone.KeyPool = key, nil, nil
+	return clone
+}
+
+// apiKey returns the API key for a call, from the key provider if any.
+func (c *Client) apiKey(ctx context.Context) (string, error) {
+	c.mu.RLock()
+	key, provider := c.Key, c.keyProvider
+	c.mu.RUnlock()
+	if provider == nil {
+		return key, nil
+	}
+	key, err := provider(ctx)
+	if err != nil {
+		return "", fmt.Errorf("hunter: key provider: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,156 @@
+package hunter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTransport answers every request, recording their
+// API keys and user agents.
+type recordingTransport struct {
+	mu         sync.Mutex
+	keys       []string
+	userAgents []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.keys = append(rt.keys, req.URL.Query().Get("api_key"))
+	rt.userAgents = append(rt.userAgents, req.Header.Get("User-Agent"))
+	rt.mu.Unlock()
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"data":{}}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestNewClient(t *testing.T) {
+	defaultTimeout := http.DefaultClient.Timeout
+	client := NewClient(WithAPIKey("test-key"), WithTimeout(5*time.Second), WithUserAgent("enrichment/1.0"))
+	if client.Key != "test-key" {
+		t.Error("expected the given key, got:", client.Key)
+	}
+	if client.client.Timeout != 5*time.Second || http.DefaultClient.Timeout != defaultTimeout {
+		t.Error("expected the timeout to be set on a copy of the default HTTP client")
+	}
+
+	if NewClient().Key != os.Getenv("HUNTER_API_KEY") {
+		t.Error("expected the HUNTER_API_KEY environment variable to be used without a key")
+	}
+	if New("test-key", nil).client != UseDefaultHTTPClient {
+		t.Error("expected New to use the default HTTP client when given nil")
+	}
+
+	rt := &recordingTransport{}
+	client = NewClient(WithAPIKey("test-key"), WithHTTPClient(&http.Client{Transport: rt}), WithUserAgent("enrichment/1.0"))
+	if _, err := client.Account(); err != nil {
+		t.Fatal(err)
+	}
+	if rt.userAgents[0] != "enrichment/1.0" {
+		t.Error("expected the user agent to be sent, got:", rt.userAgents[0])
+	}
+}
+
+func TestNewClient_features(t *testing.T) {
+	ledger, budget, pool := NewLedger(""), NewBudget(10), NewKeyPool("key-a", "key-b")
+	var dryRun strings.Builder
+	client := NewClient(
+		WithStrict(true),
+		WithRegistrableDomain(true),
+		WithLedger(ledger),
+		WithOnRecordError(func(error) {}),
+		WithBudget(budget),
+		WithDryRun(&dryRun),
+		WithKeyPool(pool),
+	)
+	if !client.Strict || !client.RegistrableDomain || client.Ledger != ledger || client.OnRecordError == nil ||
+		client.Budget != budget || client.DryRun != &dryRun || client.KeyPool != pool {
+		t.Errorf("expected every option to be set, got: %+v", client)
+	}
+	if _, err := client.DomainSearch(Params{"domain": "blog.stripe.com"}); !errors.Is(err, ErrDryRun) {
+		t.Fatal("expected ErrDryRun, got:", err)
+	}
+	if !strings.Contains(dryRun.String(), "domain=stripe.com") {
+		t.Error("expected the registrable domain in the dry run, got:", dryRun.String())
+	}
+}
+
+type tenantKey struct{}
+
+func TestWithKeyProvider(t *testing.T) {
+	rt := &recordingTransport{}
+	errNoTenant := errors.New("no tenant")
+	client := NewClient(WithHTTPClient(&http.Client{Transport: rt}), WithKeyProvider(func(ctx context.Context) (string, error) {
+		tenant, ok := ctx.Value(tenantKey{}).(string)
+		if !ok {
+			return "", errNoTenant
+		}
+		return "key-of-" + tenant, nil
+	}))
+
+	for _, tenant := range []string{"acme", "globex"} {
+		ctx := context.WithValue(context.Background(), tenantKey{}, tenant)
+		if _, err := client.AccountWithContext(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.Account(); !errors.Is(err, errNoTenant) {
+		t.Error("expected the key provider's error, got:", err)
+	}
+	if strings.Join(rt.keys, " ") != "key-of-acme key-of-globex" {
+		t.Error("expected the keys from the provider to be used, got:", rt.keys)
+	}
+}
+
+func TestClient_WithKey(t *testing.T) {
+	rt := &recordingTransport{}
+	client := NewClient(WithAPIKey("test-key"), WithHTTPClient(&http.Client{Transport: rt}), WithUserAgent("enrichment/1.0"))
+	customer := client.WithKey("customer-key")
+	customer.Strict = true
+	if client.Key != "test-key" || client.Strict {
+		t.Error("expected the client to be left unchanged")
+	}
+	if _, err := customer.Account(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Account(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(rt.keys, " ") != "customer-key test-key" {
+		t.Error("expected each client to use its own key, got:", rt.keys)
+	}
+	if rt.userAgents[0] != "enrichment/1.0" {
+		t.Error("expected the copy to keep the user agent, got:", rt.userAgents[0])
+	}
+}
+
+func TestClient_SetKey(t *testing.T) {
+	rt := &recordingTransport{}
+	client := NewClient(WithAPIKey("old-key"), WithHTTPClient(&http.Client{Transport: rt}))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Account(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	client.SetKey("new-key")
+	wg.Wait()
+	if _, err := client.Account(); err != nil {
+		t.Fatal(err)
+	}
+	if last := rt.keys[len(rt.keys)-1]; last != "new-key" {
+		t.Error("expected the new key to be used, got:", last)
+	}
+}
@@ -1,4 +1,69 @@
 package hunter
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // Params is just a type alias for a map with strings as keys and values.
 type Params = map[string]string
+
+// ErrInvalidParams is returned, wrapped with more details, when a request's
+// parameters are checked locally and found to be invalid. No request is sent
+// to the API in that case.
+var ErrInvalidParams = errors.New("the request parameters are not valid")
+
+// splitList splits a comma-delimited parameter value into its trimmed,
+// non-empty elements.
+func splitList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// joinList joins the given values into a comma-delimited parameter value.
+func joinList(values []string) string {
+	return strings.Join(values, ",")
+}
+
+// checkOneOf checks that every comma-delimited value for the given key is one
+// of the allowed values.
+func checkOneOf(params Params, key string, allowed ...string) error {
+	for _, v := range splitList(params[key]) {
+		ok := false
+		for _, a := range allowed {
+			if v == a {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%w: %q is not a valid %s, must be one of: %s", ErrInvalidParams, v, key, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// checkInt checks the value for the given key, if any, is an integer
+// within the given inclusive range.
+func checkInt(params Params, key string, low, high int) error {
+	value := params[key]
+	if value == "" {
+		return nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%w: %s must be an integer, got %q", ErrInvalidParams, key, value)
+	}
+	if i < low || i > high {
+		return fmt.Errorf("%w: %s must be between %d and %d, got %d", ErrInvalidParams, key, low, high, i)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package pattern
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrNoMatch is returned by Infer when none of the common
+// patterns match any of the examples, or there are none.
+var ErrNoMatch = errors.New("no pattern matches the examples")
+
+// Common are the patterns Infer chooses from, most common first.
+var Common = []string{
+	"{first}.{last}",
+	"{first}",
+	"{f}{last}",
+	"{first}{last}",
+	"{first}{l}",
+	"{f}.{last}",
+	"{last}",
+	"{first}_{last}",
+	"{first}-{last}",
+	"{last}.{first}",
+	"{last}{first}",
+	"{last}{f}",
+	"{first}.{l}",
+	"{last}.{f}",
+	"{last}_{first}",
+	"{f}{l}",
+}
+
+// Example is a known email address and the name of the person it belongs to.
+type Example struct {
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+// Candidate is a pattern and the number of examples it matches.
+type Candidate struct {
+	Pattern string `json:"pattern"`
+	Matches int    `json:"matches"`
+}
+
+// Inference is the most likely pattern for some examples.
+type Inference struct {
+	Pattern *Pattern
+	// Confidence is the share of the examples the pattern matches,
+	// between 0 and 1. It is less meaningful with few examples.
+	Confidence float64
+	// Matches is the number of examples the pattern matches, out of
+	// the number of Examples with both a name and an email address.
+	Matches  int
+	Examples int
+	// Candidates are every pattern matching at least one example,
+	// most matches first.
+	Candidates []Candidate
+}
+
+// MatchesOf returns the number of examples the pattern matches.
+func (i *Inference) MatchesOf(pattern string) int {
+	p, err := Parse(pattern)
+	if err != nil {
+		return 0
+	}
+	for _, c := range i.Candidates {
+		if c.Pattern == p.String() {
+			return c.Matches
+		}
+	}
+	return 0
+}
+
+// Agrees returns true if the pattern, like one returned by the API,
+// is the inferred pattern.
+func (i *Inference) Agrees(pattern string) bool {
+	p, err := Parse(pattern)
+	return err == nil && p.String() == i.Pattern.String()
+}
+
+// Infer returns the common pattern matching the most examples, which should
+// all be at the same domain. Examples without an email address or without any
+// name are ignored, and examples with only a first or a last name can only match
+// the patterns using just that name. Ties go to the more common pattern.
+func Infer(examples []Example) (*Inference, error) {
+	counts := make([]int, len(Common))
+	total := 0
+	for _, example := range examples {
+		at := strings.LastIndex(example.Email, "@")
+		if at <= 0 || (strings.TrimSpace(example.FirstName) == "" && strings.TrimSpace(example.LastName) == "") {
+			continue
+		}
+		total++
+		local := strings.ToLower(strings.TrimSpace(example.Email[:at]))
+		for i, common := range Common {
+			if matches(MustParse(common), example.FirstName, example.LastName, local) {
+				counts[i]++
+			}
+		}
+	}
+	inference := &Inference{Examples: total}
+	best := -1
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		inference.Candidates = append(inference.Candidates, Candidate{Pattern: Common[i], Matches: count})
+		if best < 0 || count > counts[best] {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil, ErrNoMatch
+	}
+	sort.SliceStable(inference.Candidates, func(i, j int) bool {
+		return inference.Candidates[i].Matches > inference.Candidates[j].Matches
+	})
+	inference.Pattern = MustParse(Common[best])
+	inference.Matches = counts[best]
+	inference.Confidence = float64(counts[best]) / float64(total)
+	return inference, nil
+}
+
+// matches returns true if the pattern gives the local part for any
+// of the ways the person's name can be written.
+func matches(p *Pattern, first, last, local string) bool {
+	for _, f := range append(Variants(first), "") {
+		for _, l := range append(Variants(last), "") {
+			if candidate, err := p.Local(f, l); err == nil && candidate == local {
+				return true
+			}
+		}
+	}
+	return false
+}
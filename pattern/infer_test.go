@@ -0,0 +1,65 @@
+package pattern
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	inference, err := Infer([]Example{
+		{FirstName: "Dustin", LastName: "Moskovitz", Email: "dmoskovitz@asana.com"},
+		{FirstName: "Justin", LastName: "Rosenstein", Email: "JRosenstein@asana.com"},
+		{FirstName: "Zoë", LastName: "van der Berg", Email: "zberg@asana.com"},
+		{FirstName: "Chris", LastName: "Farinacci", Email: "chris@asana.com"},
+		{FirstName: "", LastName: "", Email: "info@asana.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inference.Pattern.String() != "{f}{last}" {
+		t.Error("expected {f}{last}, got:", inference.Pattern)
+	}
+	if inference.Matches != 3 || inference.Examples != 4 || inference.Confidence != 0.75 {
+		t.Errorf("expected 3 of 4 examples to match, got: %d of %d (%g)", inference.Matches, inference.Examples, inference.Confidence)
+	}
+	expected := []Candidate{{"{f}{last}", 3}, {"{first}", 1}}
+	if !reflect.DeepEqual(inference.Candidates, expected) {
+		t.Errorf("expected the candidates %v, got: %v", expected, inference.Candidates)
+	}
+	if !inference.Agrees("{F}{Last}") || inference.Agrees("{first}") {
+		t.Error("expected only {f}{last} to agree")
+	}
+	if n := inference.MatchesOf("{first}"); n != 1 {
+		t.Error("expected {first} to match 1 example, got:", n)
+	}
+}
+
+func TestInfer_NoMatch(t *testing.T) {
+	_, err := Infer([]Example{{FirstName: "Dustin", LastName: "Moskovitz", Email: "ceo@asana.com"}})
+	if err != ErrNoMatch {
+		t.Error("expected ErrNoMatch, got:", err)
+	}
+	if _, err := Infer(nil); err != ErrNoMatch {
+		t.Error("expected ErrNoMatch without examples, got:", err)
+	}
+}
+
+func TestInfer_OneName(t *testing.T) {
+	inference, err := Infer([]Example{
+		{FirstName: "Dustin", LastName: "Moskovitz", Email: "dustin@asana.com"},
+		{FirstName: "Justin", Email: "justin@asana.com"},
+		{LastName: "Rosenstein", Email: "jrosenstein@asana.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inference.Pattern.String() != "{first}" {
+		t.Error("expected {first}, got:", inference.Pattern)
+	}
+	if inference.Matches != 2 || inference.Examples != 3 {
+		t.Errorf("expected 2 of 3 examples to match, got: %d of %d", inference.Matches, inference.Examples)
+	}
+	if n := inference.MatchesOf("{f}{last}"); n != 0 {
+		t.Error("expected the example with only a last name not to match {f}{last}, got:", n)
+	}
+}
@@ -0,0 +1,227 @@
+// Package pattern parses the email patterns returned by the hunter.io API,
+// like "{first}.{last}" or "{f}{last}", and generates the email addresses
+// they give for a person's name, without calling the API.
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/picatz/hunter/name"
+)
+
+// ErrInvalidPattern is returned, wrapped with more details, when
+// a pattern can't be parsed.
+var ErrInvalidPattern = errors.New("invalid email pattern")
+
+// Placeholders which can be used in a pattern.
+const (
+	First        = "{first}"
+	Last         = "{last}"
+	FirstInitial = "{f}"
+	LastInitial  = "{l}"
+)
+
+// part is either a placeholder, or a literal like "." when placeholder is empty.
+type part struct {
+	placeholder string
+	literal     string
+}
+
+// Pattern is a parsed email pattern, made of placeholders for the
+// person's name and literal characters like "." or "_" between them.
+type Pattern struct {
+	parts []part
+}
+
+// Parse parses a pattern like "{first}.{last}". It must contain at least one
+// placeholder, and its literal characters must be allowed in an email address.
+func Parse(s string) (*Pattern, error) {
+	p := &Pattern{}
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidPattern)
+	}
+	placeholders := 0
+	for rest != "" {
+		if rest[0] == '{' {
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return nil, fmt.Errorf("%w %q: unclosed placeholder", ErrInvalidPattern, s)
+			}
+			placeholder := strings.ToLower(rest[:end+1])
+			switch placeholder {
+			case First, Last, FirstInitial, LastInitial:
+			default:
+				return nil, fmt.Errorf("%w %q: unknown placeholder %s", ErrInvalidPattern, s, rest[:end+1])
+			}
+			p.parts = append(p.parts, part{placeholder: placeholder})
+			placeholders++
+			rest = rest[end+1:]
+			continue
+		}
+		end := strings.IndexByte(rest, '{')
+		if end < 0 {
+			end = len(rest)
+		}
+		literal := strings.ToLower(rest[:end])
+		for _, r := range literal {
+			if !isLocalRune(r) {
+				return nil, fmt.Errorf("%w %q: %q isn't allowed in an email address", ErrInvalidPattern, s, r)
+			}
+		}
+		p.parts = append(p.parts, part{literal: literal})
+		rest = rest[end:]
+	}
+	if placeholders == 0 {
+		return nil, fmt.Errorf("%w %q: no placeholders", ErrInvalidPattern, s)
+	}
+	return p, nil
+}
+
+// MustParse is like Parse, but panics if the pattern can't be parsed.
+func MustParse(s string) *Pattern {
+	p, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the pattern in Hunter's syntax, like "{first}.{last}".
+func (p *Pattern) String() string {
+	var b strings.Builder
+	for _, part := range p.parts {
+		b.WriteString(part.placeholder)
+		b.WriteString(part.literal)
+	}
+	return b.String()
+}
+
+// Uses returns true if the pattern contains the given placeholder.
+func (p *Pattern) Uses(placeholder string) bool {
+	for _, part := range p.parts {
+		if part.placeholder == placeholder {
+			return true
+		}
+	}
+	return false
+}
+
+// Local returns the local part of the email address given by the pattern for
+// the person's name, which must already be normalized, like "obrien". An error
+// is returned if the pattern needs a name which is empty.
+func (p *Pattern) Local(first, last string) (string, error) {
+	var b strings.Builder
+	for _, part := range p.parts {
+		var value string
+		switch part.placeholder {
+		case "":
+			b.WriteString(part.literal)
+			continue
+		case First, FirstInitial:
+			value = first
+		case Last, LastInitial:
+			value = last
+		}
+		if value == "" {
+			return "", fmt.Errorf("the pattern %s needs the %s name", p, strings.Trim(part.placeholder, "{}"))
+		}
+		if part.placeholder == FirstInitial || part.placeholder == LastInitial {
+			value = string([]rune(value)[:1])
+		}
+		b.WriteString(value)
+	}
+	return b.String(), nil
+}
+
+// Generate returns the candidate email addresses at the domain for the person,
+// most likely first. The names are normalized, removing diacritics and
+// apostrophes, and names with several parts, like "Smith-Jones" or
+// "van der Berg", give a candidate for each way they are commonly written.
+func (p *Pattern) Generate(first, last, domain string) []string {
+	var (
+		emails []string
+		seen   = map[string]bool{}
+	)
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	for _, f := range Variants(first) {
+		for _, l := range Variants(last) {
+			local, err := p.Local(f, l)
+			if err != nil || seen[local] {
+				continue
+			}
+			seen[local] = true
+			emails = append(emails, local+"@"+domain)
+		}
+	}
+	return emails
+}
+
+// Variants returns the ways a name is commonly written in email
+// addresses, most common first. For example, "van der Berg" gives
+// "vanderberg" and "berg", and "Smith-Jones" gives "smithjones",
+// "smith-jones", "smith" and "jones".
+func Variants(full string) []string {
+	words := strings.FieldsFunc(Normalize(full), func(r rune) bool {
+		return r == ' ' || r == '-'
+	})
+	if len(words) == 0 {
+		return nil
+	}
+	var (
+		variants []string
+		seen     = map[string]bool{}
+	)
+	add := func(variant string) {
+		if variant != "" && !seen[variant] {
+			seen[variant] = true
+			variants = append(variants, variant)
+		}
+	}
+	add(strings.Join(words, ""))
+	if strings.Contains(full, "-") {
+		add(strings.Join(words, "-"))
+	}
+	var withoutParticles []string
+	for _, word := range words {
+		if !name.IsParticle(word) {
+			withoutParticles = append(withoutParticles, word)
+		}
+	}
+	if len(withoutParticles) > 0 && len(withoutParticles) < len(words) {
+		add(strings.Join(withoutParticles, ""))
+	}
+	if len(withoutParticles) > 1 {
+		add(withoutParticles[0])
+		add(withoutParticles[len(withoutParticles)-1])
+	}
+	return variants
+}
+
+// Normalize lowercases the name and removes what isn't used in email
+// addresses: diacritics are transliterated using name.Transliterate, like "é"
+// to "e" or "ß" to "ss", apostrophes and periods are removed, and spaces and
+// hyphens are kept.
+func Normalize(full string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name.Transliterate(full)) {
+		switch {
+		case r == ' ' || r == '-':
+			b.WriteRune(r)
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// isLocalRune returns true if the rune may be used as a
+// literal in the local part of an email address.
+func isLocalRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(".-_+", r))
+}
@@ -0,0 +1,105 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, s := range []string{"{first}", "{first}.{last}", "{f}{last}", "{First}_{L}", "{last}-{first}2"} {
+		if _, err := Parse(s); err != nil {
+			t.Errorf("expected %q to parse, got: %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "first.last", "{first}.{middle}", "{first", "{first}@{last}", "{first} {last}"} {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalidPattern) {
+			t.Errorf("expected %q to be invalid, got: %v", s, err)
+		}
+	}
+	if s := MustParse("{First}.{LAST}").String(); s != "{first}.{last}" {
+		t.Error("expected the pattern to be lowercased, got:", s)
+	}
+}
+
+func TestPattern_Local(t *testing.T) {
+	tests := []struct {
+		pattern string
+		local   string
+	}{
+		{"{first}", "dustin"},
+		{"{first}.{last}", "dustin.moskovitz"},
+		{"{f}{last}", "dmoskovitz"},
+		{"{first}{l}", "dustinm"},
+		{"{last}_{f}", "moskovitz_d"},
+	}
+	for _, test := range tests {
+		local, err := MustParse(test.pattern).Local("dustin", "moskovitz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if local != test.local {
+			t.Errorf("expected %s to give %q, got: %q", test.pattern, test.local, local)
+		}
+	}
+	if _, err := MustParse("{f}.{last}").Local("dustin", ""); err == nil {
+		t.Error("expected an error without the last name")
+	}
+}
+
+func TestVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []string
+	}{
+		{"Dustin", []string{"dustin"}},
+		{"José", []string{"jose"}},
+		{"Strauß", []string{"strauss"}},
+		{"O'Brien", []string{"obrien"}},
+		{"Smith-Jones", []string{"smithjones", "smith-jones", "smith", "jones"}},
+		{"van der Berg", []string{"vanderberg", "berg"}},
+		{"Nguyễn", []string{"nguyen"}},
+		{"Đặng", []string{"dang"}},
+		{"Dvořák", []string{"dvorak"}},
+		{"Ştefan", []string{"stefan"}},
+		{"ＪＯＨＮ", []string{"john"}},
+		{"Пётр", nil},
+		{"  ", nil},
+	}
+	for _, test := range tests {
+		if variants := Variants(test.name); !reflect.DeepEqual(variants, test.variants) {
+			t.Errorf("expected %q to give %q, got: %q", test.name, test.variants, variants)
+		}
+	}
+}
+
+func TestPattern_Generate(t *testing.T) {
+	emails := MustParse("{f}{last}").Generate("Zoë", "van der Berg", "Example.com")
+	expected := []string{"zvanderberg@example.com", "zberg@example.com"}
+	if !reflect.DeepEqual(emails, expected) {
+		t.Errorf("expected %q, got: %q", expected, emails)
+	}
+	emails = MustParse("{first}.{last}").Generate("Nguyễn", "Trần", "x.com")
+	if expected := []string{"nguyen.tran@x.com"}; !reflect.DeepEqual(emails, expected) {
+		t.Errorf("expected %q, got: %q", expected, emails)
+	}
+	if emails := MustParse("{first}.{last}").Generate("Пётр", "Чайковский", "x.com"); len(emails) != 0 {
+		t.Error("expected no emails for names which can't be transliterated, got:", emails)
+	}
+	if emails := MustParse("{first}.{last}").Generate("Dustin", "", "asana.com"); len(emails) != 0 {
+		t.Error("expected no emails without the last name, got:", emails)
+	}
+}
+
+func ExamplePattern_Generate() {
+	p, _ := Parse("{first}.{last}")
+	for _, email := range p.Generate("Renée", "O'Connor-Lévesque", "example.com") {
+		fmt.Println(email)
+	}
+	// Output:
+	// renee.oconnorlevesque@example.com
+	// renee.oconnor-levesque@example.com
+	// renee.oconnor@example.com
+	// renee.levesque@example.com
+}
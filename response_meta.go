@@ -0,0 +1,59 @@
+package hunter
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// ResponseMeta describes the HTTP response a result was decoded from.
+type ResponseMeta struct {
+	// Endpoint is the API endpoint that was called, like "domain-search".
+	Endpoint   string `json:"endpoint"`
+	StatusCode int    `json:"status_code"`
+	RequestID  string `json:"request_id,omitempty"`
+	// KeyID is a redacted form of the API key used for the call.
+	KeyID    string        `json:"key_id,omitempty"`
+	Duration time.Duration `json:"duration"`
+	// RateLimitLimit, RateLimitRemaining and RateLimitReset are taken from
+	// the X-RateLimit-* headers, and are zero if they weren't sent.
+	RateLimitLimit     int       `json:"rate_limit_limit,omitempty"`
+	RateLimitRemaining int       `json:"rate_limit_remaining,omitempty"`
+	RateLimitReset     time.Time `json:"rate_limit_reset,omitempty"`
+	// Credits is an estimate of the credits the call consumed: a domain search
+	// or email finder call counts only when it returns results, a verification
+	// is half a credit, and account and email count calls are free.
+	Credits float64 `json:"credits"`
+	// OriginalDomain is the domain param as given, or the domain of the email
+	// param, and Domain is the normalized form which was sent.
+	OriginalDomain string `json:"original_domain,omitempty"`
+	Domain         string `json:"domain,omitempty"`
+}
+
+// ConsumedCredit returns true if the call likely consumed a credit.
+func (m *ResponseMeta) ConsumedCredit() bool {
+	return m.Credits > 0
+}
+
+// newResponseMeta returns the metadata for the response to a request
+// to the given URL which started at the given time.
+func newResponseMeta(url string, resp *http.Response, start time.Time) *ResponseMeta {
+	meta := &ResponseMeta{
+		Endpoint:   path.Base(url),
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		Duration:   now().Sub(start),
+	}
+	meta.RateLimitLimit, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	meta.RateLimitRemaining, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		// the reset is sent as either a unix timestamp or a number of seconds from now
+		if reset > 1e9 {
+			meta.RateLimitReset = time.Unix(reset, 0)
+		} else {
+			meta.RateLimitReset = start.Add(time.Duration(reset) * time.Second)
+		}
+	}
+	return meta
+}
@@ -0,0 +1,157 @@
+package hunter
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_ResponseMeta(t *testing.T) {
+	client := New("test-key", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Header: http.Header{
+					"X-Ratelimit-Limit":     []string{"15"},
+					"X-Ratelimit-Remaining": []string{"14"},
+					"X-Ratelimit-Reset":     []string{"60"},
+					"X-Request-Id":          []string{"abc123"},
+				},
+				Body:    ioutil.NopCloser(strings.NewReader(`{"data":{"emails":[{"value":"patrick@stripe.com"}]}}`)),
+				Request: req,
+			}, nil
+		}),
+	})
+	result, err := client.DomainSearch(Params{"domain": "stripe.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := result.Response
+	if meta.Endpoint != "domain-search" || meta.StatusCode != 200 || meta.RequestID != "abc123" {
+		t.Errorf("unexpected response meta: %+v", meta)
+	}
+	if meta.RateLimitLimit != 15 || meta.RateLimitRemaining != 14 {
+		t.Errorf("unexpected rate limit: %+v", meta)
+	}
+	if until := time.Until(meta.RateLimitReset); until <= 0 || until > time.Minute {
+		t.Error("expected the rate limit to reset within a minute, got:", meta.RateLimitReset)
+	}
+	if !meta.ConsumedCredit() {
+		t.Error("expected a search with results to consume a credit")
+	}
+}
+
+func TestClient_APIErrorMeta(t *testing.T) {
+	client := New("test-key", &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 429,
+				Header: http.Header{
+					"X-Ratelimit-Limit":     []string{"15"},
+					"X-Ratelimit-Remaining": []string{"0"},
+					"X-Ratelimit-Reset":     []string{"30"},
+				},
+				Body:    ioutil.NopCloser(strings.NewReader(`{"errors":[]}`)),
+				Request: req,
+			}, nil
+		}),
+	})
+	_, err := client.FindEmail(Params{"domain": "stripe.com", "full_name": "Patrick Collison"})
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Fatal("expected ErrTooManyRequests, got:", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Meta == nil {
+		t.Fatal("expected an *APIError with the response meta, got:", err)
+	}
+	if apiErr.Meta.StatusCode != 429 || apiErr.Meta.Endpoint != "email-finder" || apiErr.Meta.RateLimitRemaining != 0 || apiErr.Meta.RateLimitLimit != 15 {
+		t.Errorf("unexpected response meta: %+v", apiErr.Meta)
+	}
+	if until := time.Until(apiErr.Meta.RateLimitReset); until <= 0 || until > time.Minute {
+		t.Error("expected the rate limit reset time, got:", apiErr.Meta.RateLimitReset)
+	}
+}
+
+func TestResponseMeta_Credits(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		call    func(*Client) (*ResponseMeta, error)
+		credits float64
+	}{
+		{
+			name: "search without results",
+			body: `{"data":{"emails":[]}}`,
+			call: func(c *Client) (*ResponseMeta, error) {
+				r, err := c.DomainSearch(Params{"domain": "example.com"})
+				if err != nil {
+					return nil, err
+				}
+				return r.Response, nil
+			},
+			credits: 0,
+		},
+		{
+			name: "finder with email",
+			body: `{"data":{"email":"dustin@asana.com"}}`,
+			call: func(c *Client) (*ResponseMeta, error) {
+				r, err := c.FindEmail(Params{"domain": "asana.com", "full_name": "Dustin Moskovitz"})
+				if err != nil {
+					return nil, err
+				}
+				return r.Response, nil
+			},
+			credits: 1,
+		},
+		{
+			name: "finder without email",
+			body: `{"data":{"email":null}}`,
+			call: func(c *Client) (*ResponseMeta, error) {
+				r, err := c.FindEmail(Params{"domain": "asana.com", "full_name": "Nobody"})
+				if err != nil {
+					return nil, err
+				}
+				return r.Response, nil
+			},
+			credits: 0,
+		},
+		{
+			name: "verification",
+			body: `{"data":{"email":"steli@close.io"}}`,
+			call: func(c *Client) (*ResponseMeta, error) {
+				r, err := c.VerifyEmail(Params{"email": "steli@close.io"})
+				if err != nil {
+					return nil, err
+				}
+				return r.Response, nil
+			},
+			credits: 0.5,
+		},
+		{
+			name: "account",
+			body: `{"data":{}}`,
+			call: func(c *Client) (*ResponseMeta, error) {
+				r, err := c.Account()
+				if err != nil {
+					return nil, err
+				}
+				return r.Response, nil
+			},
+			credits: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta, err := test.call(newTestClient(200, test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if meta.Credits != test.credits {
+				t.Errorf("got %v credits, want %v", meta.Credits, test.credits)
+			}
+		})
+	}
+}
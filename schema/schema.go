@@ -0,0 +1,267 @@
+// Package schema compares JSON response bodies from the hunter.io API to the
+// Go types they are decoded into, to catch changes to the API that would
+// otherwise be silently dropped or decoded into zero values.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// IssueKind describes how a response differs from a Go type.
+type IssueKind string
+
+// Kinds of issues reported by Check.
+const (
+	// Missing means the Go type has a field the response didn't include,
+	// unless the field is tagged with omitempty.
+	Missing IssueKind = "missing"
+	// Extra means the response has a field the Go type doesn't model.
+	Extra IssueKind = "extra"
+	// WrongType means the response has a value the Go type can't decode.
+	WrongType IssueKind = "wrong type"
+)
+
+// Issue is a difference between a response and a Go type. The Path uses
+// dots for object keys and "[]" for array elements, like "data.emails[].value".
+type Issue struct {
+	Path string
+	Kind IssueKind
+	// Want is the JSON type expected by the Go type, if known.
+	Want string
+	// Got is the JSON type found in the response, if any.
+	Got string
+}
+
+// String returns a human readable description of the issue.
+func (i Issue) String() string {
+	switch i.Kind {
+	case WrongType:
+		return fmt.Sprintf("%s: %s, want %s, got %s", i.Path, i.Kind, i.Want, i.Got)
+	case Extra:
+		return fmt.Sprintf("%s: %s %s", i.Path, i.Kind, i.Got)
+	default:
+		return fmt.Sprintf("%s: %s %s", i.Path, i.Kind, i.Want)
+	}
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Check compares the JSON body to the type of v, which is usually a pointer
+// to one of the hunter result types, and returns the differences sorted by path.
+// Null values are never reported, since they decode into zero values.
+func Check(body []byte, v interface{}) ([]Issue, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	c := &checker{seen: map[Issue]bool{}}
+	c.check("", value, reflect.TypeOf(v))
+	sort.Slice(c.issues, func(i, j int) bool {
+		if c.issues[i].Path != c.issues[j].Path {
+			return c.issues[i].Path < c.issues[j].Path
+		}
+		return c.issues[i].Kind < c.issues[j].Kind
+	})
+	return c.issues, nil
+}
+
+type checker struct {
+	issues []Issue
+	seen   map[Issue]bool
+}
+
+func (c *checker) report(issue Issue) {
+	if !c.seen[issue] {
+		c.seen[issue] = true
+		c.issues = append(c.issues, issue)
+	}
+}
+
+func (c *checker) check(path string, value interface{}, t reflect.Type) {
+	if value == nil {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		c.checkUnmarshaler(path, value, t)
+		return
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			c.report(Issue{Path: path, Kind: WrongType, Want: "object", Got: jsonType(value)})
+			return
+		}
+		c.checkStruct(path, object, t)
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			c.report(Issue{Path: path, Kind: WrongType, Want: "object", Got: jsonType(value)})
+			return
+		}
+		for key, v := range object {
+			c.check(join(path, key), v, t.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		array, ok := value.([]interface{})
+		if !ok {
+			c.report(Issue{Path: path, Kind: WrongType, Want: "array", Got: jsonType(value)})
+			return
+		}
+		for _, v := range array {
+			c.check(path+"[]", v, t.Elem())
+		}
+	case reflect.String:
+		c.checkScalar(path, value, "string")
+	case reflect.Bool:
+		c.checkScalar(path, value, "boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := value.(json.Number); ok {
+			if _, err := n.Int64(); err != nil {
+				c.report(Issue{Path: path, Kind: WrongType, Want: "integer", Got: "number"})
+			}
+			return
+		}
+		c.checkScalar(path, value, "integer")
+	case reflect.Float32, reflect.Float64:
+		c.checkScalar(path, value, "number")
+	}
+}
+
+func (c *checker) checkScalar(path string, value interface{}, want string) {
+	got := jsonType(value)
+	if got != want {
+		c.report(Issue{Path: path, Kind: WrongType, Want: want, Got: got})
+	}
+}
+
+// checkUnmarshaler checks types with custom decoding by trying to decode the value.
+func (c *checker) checkUnmarshaler(path string, value interface{}, t reflect.Type) {
+	if t == reflect.TypeOf(json.RawMessage{}) {
+		return
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(b, reflect.New(t).Interface()); err != nil {
+		c.report(Issue{Path: path, Kind: WrongType, Want: t.String(), Got: jsonType(value)})
+	}
+}
+
+func (c *checker) checkStruct(path string, object map[string]interface{}, t reflect.Type) {
+	fields, optional := map[string]reflect.Type{}, map[string]bool{}
+	collectFields(t, fields, optional)
+	for name, ft := range fields {
+		v, ok := object[name]
+		if !ok {
+			if optional[name] {
+				continue
+			}
+			c.report(Issue{Path: join(path, name), Kind: Missing, Want: jsonTypeOf(ft)})
+			continue
+		}
+		c.check(join(path, name), v, ft)
+	}
+	for key, v := range object {
+		if _, ok := fields[key]; !ok {
+			c.report(Issue{Path: join(path, key), Kind: Extra, Got: jsonType(v)})
+		}
+	}
+}
+
+// collectFields collects the JSON field names of the struct type, following
+// the encoding/json rules for tags and embedded structs. Fields tagged with
+// omitempty are optional, since the API doesn't always include them.
+func collectFields(t reflect.Type, fields map[string]reflect.Type, optional map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectFields(ft, fields, optional)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+		optional[name] = strings.Contains(tag, ",omitempty")
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonType returns the JSON type name of a decoded value.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// jsonTypeOf returns the JSON type name expected for a Go type.
+func jsonTypeOf(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return t.String()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Interface:
+		return "any"
+	}
+	return "integer"
+}
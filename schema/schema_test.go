@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type phone struct {
+	Number string
+}
+
+func (p *phone) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, &p.Number)
+}
+
+type page struct {
+	Limit int `json:"limit"`
+}
+
+type result struct {
+	Data struct {
+		Email   string   `json:"email"`
+		Score   int      `json:"score"`
+		Webmail bool     `json:"webmail"`
+		Phone   phone    `json:"phone_number"`
+		Tags    []string `json:"tags"`
+		// Note is optional, so never missing
+		Note string `json:"note,omitempty"`
+	} `json:"data"`
+	Meta struct {
+		page
+	} `json:"meta"`
+	Raw json.RawMessage `json:"-"`
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		issues []Issue
+	}{
+		{
+			name: "matching",
+			body: `{"data":{"email":"a@b.c","score":1,"webmail":false,"phone_number":"+1","tags":["x"]},"meta":{"limit":10}}`,
+		},
+		{
+			name: "nulls",
+			body: `{"data":{"email":null,"score":null,"webmail":null,"phone_number":null,"tags":null},"meta":{"limit":null}}`,
+		},
+		{
+			name: "missing",
+			body: `{"data":{"email":"a@b.c","score":1,"webmail":false,"phone_number":null},"meta":{}}`,
+			issues: []Issue{
+				{Path: "data.tags", Kind: Missing, Want: "array"},
+				{Path: "meta.limit", Kind: Missing, Want: "integer"},
+			},
+		},
+		{
+			name: "extra",
+			body: `{"data":{"email":"a@b.c","score":1,"webmail":false,"phone_number":null,"tags":[],"accept_all":true},"meta":{"limit":10},"errors":[]}`,
+			issues: []Issue{
+				{Path: "data.accept_all", Kind: Extra, Got: "boolean"},
+				{Path: "errors", Kind: Extra, Got: "array"},
+			},
+		},
+		{
+			name: "wrong types",
+			body: `{"data":{"email":1,"score":1.5,"webmail":"no","phone_number":{"number":"+1"},"tags":[1,"x",2]},"meta":{"limit":"10"}}`,
+			issues: []Issue{
+				{Path: "data.email", Kind: WrongType, Want: "string", Got: "number"},
+				{Path: "data.phone_number", Kind: WrongType, Want: "schema.phone", Got: "object"},
+				{Path: "data.score", Kind: WrongType, Want: "integer", Got: "number"},
+				{Path: "data.tags[]", Kind: WrongType, Want: "string", Got: "number"},
+				{Path: "data.webmail", Kind: WrongType, Want: "boolean", Got: "string"},
+				{Path: "meta.limit", Kind: WrongType, Want: "integer", Got: "string"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issues, err := Check([]byte(test.body), new(result))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(issues, test.issues) {
+				t.Errorf("got issues %v, want %v", issues, test.issues)
+			}
+		})
+	}
+}
+
+func TestCheck_invalidJSON(t *testing.T) {
+	if _, err := Check([]byte(`{"data":`), new(result)); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
+
+func ExampleCheck() {
+	var v struct {
+		PhoneNumber string `json:"phone_number"`
+	}
+	issues, _ := Check([]byte(`{"phone_number":{"number":"+1 415 555 0100"}}`), &v)
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	// Output: phone_number: wrong type, want string, got object
+}
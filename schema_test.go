@@ -0,0 +1,141 @@
+package hunter
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/picatz/hunter/schema"
+)
+
+// record makes TestRecordResponses replace the responses in testdata with
+// live ones, which uses the HUNTER_API_KEY environment variable and spends
+// a few credits:
+//
+//	go test -run TestRecordResponses -record
+var record = flag.Bool("record", false, "record live responses into testdata")
+
+// redactedFields are replaced in recorded responses, since they
+// describe the owner of the API key.
+var redactedFields = map[string]interface{}{
+	"first_name": "REDACTED",
+	"last_name":  "REDACTED",
+	"email":      "REDACTED@example.com",
+	"team_id":    0,
+}
+
+func TestRecordResponses(t *testing.T) {
+	if !*record {
+		t.Skip("use -record to record live responses")
+	}
+	client := New(UseDefaultEnvVariable, UseDefaultHTTPClient)
+	responses := map[string]func() (json.RawMessage, error){
+		"account.json": func() (json.RawMessage, error) {
+			r, err := client.Account()
+			if err != nil {
+				return nil, err
+			}
+			return r.Raw, nil
+		},
+		"domain_search.json": func() (json.RawMessage, error) {
+			r, err := client.DomainSearch(Params{"domain": "intercom.com", "limit": "2"})
+			if err != nil {
+				return nil, err
+			}
+			return r.Raw, nil
+		},
+		"email_count.json": func() (json.RawMessage, error) {
+			r, err := client.CountEmails(Params{"domain": "stripe.com"})
+			if err != nil {
+				return nil, err
+			}
+			return r.Raw, nil
+		},
+		"email_finder.json": func() (json.RawMessage, error) {
+			r, err := client.FindEmail(Params{"domain": "reddit.com", "first_name": "Alexis", "last_name": "Ohanian"})
+			if err != nil {
+				return nil, err
+			}
+			return r.Raw, nil
+		},
+		"email_verifier.json": func() (json.RawMessage, error) {
+			r, err := client.VerifyEmail(Params{"email": "patrick@stripe.com"})
+			if err != nil {
+				return nil, err
+			}
+			return r.Raw, nil
+		},
+	}
+	for file, get := range responses {
+		body, err := get()
+		if err != nil {
+			t.Fatal(file, err)
+		}
+		if file == "account.json" {
+			if body, err = redact(body); err != nil {
+				t.Fatal(file, err)
+			}
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, body, "", "  "); err != nil {
+			t.Fatal(file, err)
+		}
+		out.WriteByte('\n')
+		if err := ioutil.WriteFile(filepath.Join("testdata", file), out.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// redact replaces the redactedFields of the response's data.
+func redact(body []byte) ([]byte, error) {
+	var response map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	for name, value := range redactedFields {
+		if _, ok := response["data"][name]; ok {
+			response["data"][name], _ = json.Marshal(value)
+		}
+	}
+	return json.Marshal(response)
+}
+
+// TestResultSchemas checks the recorded responses in testdata still decode
+// into the result types. Fields the types don't model yet are only logged.
+// The responses were taken from the examples in the API documentation, with
+// the account's owner redacted, and can be recorded again with -record.
+func TestResultSchemas(t *testing.T) {
+	tests := []struct {
+		file   string
+		result interface{}
+	}{
+		{"account.json", new(AccountInformation)},
+		{"domain_search.json", new(DomainSearchResult)},
+		{"email_count.json", new(EmailCounterResult)},
+		{"email_finder.json", new(EmailFinderResult)},
+		{"email_verifier.json", new(EmailVerifierResult)},
+	}
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			body, err := ioutil.ReadFile(filepath.Join("testdata", test.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			issues, err := schema.Check(body, test.result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, issue := range issues {
+				if issue.Kind == schema.Extra {
+					t.Log(issue)
+					continue
+				}
+				t.Error(issue)
+			}
+		})
+	}
+}
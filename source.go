@@ -0,0 +1,40 @@
+package hunter
+
+import "time"
+
+// Source is a page on the web where an email address was found.
+type Source struct {
+	Domain      string `json:"domain"`
+	URI         string `json:"uri"`
+	ExtractedOn Date   `json:"extracted_on"`
+	LastSeenOn  Date   `json:"last_seen_on"`
+	StillOnPage bool   `json:"still_on_page"`
+}
+
+// Age returns how long ago the source was last seen, or how long
+// ago it was first found if it was never seen again.
+func (s Source) Age() time.Duration {
+	if !s.LastSeenOn.IsZero() {
+		return now().Sub(s.LastSeenOn.Time)
+	}
+	return now().Sub(s.ExtractedOn.Time)
+}
+
+// lastSeen returns the most recent date the source was seen on.
+func (s Source) lastSeen() time.Time {
+	if s.LastSeenOn.After(s.ExtractedOn.Time) {
+		return s.LastSeenOn.Time
+	}
+	return s.ExtractedOn.Time
+}
+
+// mostRecentSource returns the most recently seen of the given sources, or nil.
+func mostRecentSource(sources []Source) *Source {
+	var recent *Source
+	for i := range sources {
+		if recent == nil || sources[i].lastSeen().After(recent.lastSeen()) {
+			recent = &sources[i]
+		}
+	}
+	return recent
+}
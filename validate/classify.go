@@ -0,0 +1,222 @@
+package validate
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Class is the kind of email provider a domain belongs to.
+type Class string
+
+// Classes of domains returned by Classify.
+const (
+	// ClassCorporate is any domain which isn't a known webmail or
+	// disposable domain, usually a company's own domain.
+	ClassCorporate Class = "corporate"
+	// ClassWebmail is a free webmail provider, like gmail.com.
+	ClassWebmail Class = "webmail"
+	// ClassDisposable is a temporary email service, like mailinator.com.
+	ClassDisposable Class = "disposable"
+)
+
+// DomainList holds the known webmail and disposable domains.
+// It is safe for concurrent use, and its zero value is an empty list.
+type DomainList struct {
+	mu         sync.RWMutex
+	webmail    map[string]bool
+	disposable map[string]bool
+}
+
+// NewDomainList returns a list of the given domains.
+func NewDomainList(webmail, disposable []string) *DomainList {
+	l := &DomainList{}
+	l.Add(ClassWebmail, webmail...)
+	l.Add(ClassDisposable, disposable...)
+	return l
+}
+
+// DefaultDomainList returns a copy of the list embedded in the package.
+func DefaultDomainList() *DomainList {
+	return NewDomainList(defaultWebmail, defaultDisposable)
+}
+
+// Domains is the list used by Classify, which starts as the
+// DefaultDomainList. Replace or add to it to update it.
+var Domains = DefaultDomainList()
+
+// Classify returns the class of the domain using the Domains list.
+func Classify(domain string) Class {
+	return Domains.Classify(domain)
+}
+
+// Classify returns the class of the domain, which is also the class of its
+// subdomains, like "eu.mailinator.com". Disposable domains take precedence.
+func (l *DomainList) Classify(domain string) Class {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for d := domain; d != ""; {
+		if l.disposable[d] {
+			return ClassDisposable
+		}
+		if l.webmail[d] {
+			return ClassWebmail
+		}
+		i := strings.IndexByte(d, '.')
+		if i < 0 {
+			break
+		}
+		d = d[i+1:]
+	}
+	return ClassCorporate
+}
+
+// Add adds the domains to the list for the webmail or disposable class.
+func (l *DomainList) Add(class Class, domains ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	set := l.set(class)
+	for _, domain := range domains {
+		if domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), "."); domain != "" {
+			set[domain] = true
+		}
+	}
+}
+
+// Replace replaces the domains of the webmail or disposable class.
+func (l *DomainList) Replace(class Class, domains ...string) {
+	l.mu.Lock()
+	switch class {
+	case ClassWebmail:
+		l.webmail = map[string]bool{}
+	case ClassDisposable:
+		l.disposable = map[string]bool{}
+	}
+	l.mu.Unlock()
+	l.Add(class, domains...)
+}
+
+// Len returns the number of domains of the class.
+func (l *DomainList) Len(class Class) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	switch class {
+	case ClassWebmail:
+		return len(l.webmail)
+	case ClassDisposable:
+		return len(l.disposable)
+	}
+	return 0
+}
+
+// set returns the domains of the class, creating them if needed,
+// which must be called with the lock held for writing.
+func (l *DomainList) set(class Class) map[string]bool {
+	switch class {
+	case ClassWebmail:
+		if l.webmail == nil {
+			l.webmail = map[string]bool{}
+		}
+		return l.webmail
+	case ClassDisposable:
+		if l.disposable == nil {
+			l.disposable = map[string]bool{}
+		}
+		return l.disposable
+	}
+	return map[string]bool{}
+}
+
+// domainListJSON is how a DomainList is encoded.
+type domainListJSON struct {
+	Webmail    []string `json:"webmail"`
+	Disposable []string `json:"disposable"`
+}
+
+// MarshalJSON encodes the list as an object of sorted domains by class.
+func (l *DomainList) MarshalJSON() ([]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return json.Marshal(domainListJSON{
+		Webmail:    sortedKeys(l.webmail),
+		Disposable: sortedKeys(l.disposable),
+	})
+}
+
+// UnmarshalJSON decodes a list encoded by MarshalJSON.
+func (l *DomainList) UnmarshalJSON(data []byte) error {
+	var v domainListJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	decoded := NewDomainList(v.Webmail, v.Disposable)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.webmail, l.disposable = decoded.webmail, decoded.disposable
+	return nil
+}
+
+// ReadDomains reads a list of domains, one per line, like the lists of
+// disposable domains published online. Blank lines and comments starting
+// with "#" or "//" are skipped.
+func ReadDomains(r io.Reader) ([]string, error) {
+	var domains []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		domains = append(domains, strings.Fields(line)[0])
+	}
+	return domains, scanner.Err()
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// defaultWebmail are the embedded webmail domains.
+var defaultWebmail = []string{
+	"aol.com", "att.net", "bellsouth.net", "btinternet.com", "comcast.net", "cox.net",
+	"earthlink.net", "fastmail.com", "free.fr", "gmail.com", "gmx.at", "gmx.com", "gmx.de",
+	"gmx.net", "googlemail.com", "hey.com", "hotmail.co.uk", "hotmail.com", "hotmail.de",
+	"hotmail.es", "hotmail.fr", "hotmail.it", "icloud.com", "inbox.ru", "laposte.net",
+	"libero.it", "list.ru", "live.co.uk", "live.com", "live.fr", "mac.com", "mail.com",
+	"mail.ru", "me.com", "msn.com", "naver.com", "netscape.net", "orange.fr", "outlook.com",
+	"outlook.de", "outlook.fr", "pm.me", "proton.me", "protonmail.com", "qq.com", "rambler.ru",
+	"rediffmail.com", "rocketmail.com", "sbcglobal.net", "sfr.fr", "shaw.ca", "t-online.de",
+	"tutanota.com", "verizon.net", "virgilio.it", "wanadoo.fr", "web.de", "yahoo.ca",
+	"yahoo.co.in", "yahoo.co.jp", "yahoo.co.uk", "yahoo.com", "yahoo.com.br", "yahoo.de",
+	"yahoo.es", "yahoo.fr", "yahoo.it", "yandex.com", "yandex.ru", "ymail.com", "zoho.com",
+	"163.com", "126.com", "sina.com", "daum.net", "hanmail.net", "seznam.cz", "wp.pl",
+	"o2.pl", "onet.pl", "interia.pl", "bigpond.com", "optusnet.com.au", "uol.com.br",
+	"bol.com.br", "terra.com.br",
+}
+
+// defaultDisposable are the embedded disposable domains.
+var defaultDisposable = []string{
+	"10minutemail.com", "10minutemail.net", "20minutemail.com", "33mail.com", "1secmail.com",
+	"1secmail.net", "1secmail.org", "anonbox.net", "burnermail.io", "discard.email",
+	"dispostable.com", "dropmail.me", "einrot.com", "emailfake.com", "emailondeck.com",
+	"fakeinbox.com", "generator.email", "getairmail.com", "getnada.com", "grr.la",
+	"guerrillamail.biz", "guerrillamail.com", "guerrillamail.de", "guerrillamail.info",
+	"guerrillamail.net", "guerrillamail.org", "guerrillamailblock.com", "harakirimail.com",
+	"inboxkitten.com", "jetable.org", "mailcatch.com", "maildrop.cc", "mailforspam.com",
+	"mailinator.com", "mailinator.net", "mailinator2.com", "mailnesia.com", "mailpoof.com",
+	"mailsac.com", "mintemail.com", "moakt.com", "mohmal.com", "mytemp.email", "nada.email",
+	"pokemail.net", "sharklasers.com", "spam4.me", "spambox.us", "spamfree24.org",
+	"spamgourmet.com", "tempail.com", "temp-mail.io", "temp-mail.org", "tempinbox.com",
+	"tempmail.dev", "tempmail.net", "tempmailo.com", "tempr.email", "throwawaymail.com",
+	"tmpmail.org", "trashmail.com", "trashmail.de", "trashmail.net", "wegwerfmail.de",
+	"yopmail.com", "yopmail.fr", "yopmail.net",
+}
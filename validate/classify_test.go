@@ -0,0 +1,81 @@
+package validate
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		domain string
+		class  Class
+	}{
+		{"gmail.com", ClassWebmail},
+		{"GMail.com.", ClassWebmail},
+		{"mailinator.com", ClassDisposable},
+		{"eu.mailinator.com", ClassDisposable},
+		{"close.io", ClassCorporate},
+		{"notgmail.com", ClassCorporate},
+	}
+	for _, test := range tests {
+		if class := Classify(test.domain); class != test.class {
+			t.Errorf("expected %s to be %s, got: %s", test.domain, test.class, class)
+		}
+	}
+	if r := Email("someone@yopmail.com"); r.Class != ClassDisposable {
+		t.Error("expected the result to be disposable, got:", r.Class)
+	}
+}
+
+func TestDomainList(t *testing.T) {
+	l := NewDomainList([]string{"Webmail.example"}, nil)
+	l.Add(ClassDisposable, "temp.example", "")
+	if l.Classify("webmail.example") != ClassWebmail || l.Classify("temp.example") != ClassDisposable {
+		t.Error("expected the domains to be classified")
+	}
+	l.Replace(ClassDisposable, "other.example")
+	if l.Classify("temp.example") != ClassCorporate || l.Len(ClassDisposable) != 1 {
+		t.Error("expected the disposable domains to be replaced")
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"webmail":["webmail.example"],"disposable":["other.example"]}` {
+		t.Error("unexpected encoding:", string(b))
+	}
+	var decoded DomainList
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Classify("other.example") != ClassDisposable {
+		t.Error("expected the decoded list to be the same")
+	}
+}
+
+func TestDomainList_zero(t *testing.T) {
+	var l DomainList
+	if l.Classify("mailinator.com") != ClassCorporate || l.Len(ClassWebmail) != 0 {
+		t.Error("expected the zero value to be an empty list")
+	}
+	l.Add(ClassDisposable, "temp.example")
+	l.Replace(ClassWebmail, "webmail.example")
+	if l.Classify("temp.example") != ClassDisposable || l.Classify("webmail.example") != ClassWebmail {
+		t.Error("expected domains to be added to the zero value")
+	}
+	if b, err := json.Marshal(&l); err != nil || string(b) != `{"webmail":["webmail.example"],"disposable":["temp.example"]}` {
+		t.Error("unexpected encoding:", string(b), err)
+	}
+}
+
+func TestReadDomains(t *testing.T) {
+	domains, err := ReadDomains(strings.NewReader("# disposable domains\n\nmailinator.com\n// another comment\n  yopmail.com  # trailing\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"mailinator.com", "yopmail.com"}; !reflect.DeepEqual(domains, expected) {
+		t.Errorf("expected %v, got: %v", expected, domains)
+	}
+}
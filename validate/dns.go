@@ -0,0 +1,186 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Resolver looks up the DNS records used to check a domain can receive email.
+// It is implemented by *net.Resolver, and FakeResolver for tests.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DefaultResolver is the resolver used when nil is given.
+var DefaultResolver Resolver = net.DefaultResolver
+
+// DomainCheck is what DNS says about a domain receiving email.
+type DomainCheck struct {
+	Domain string `json:"domain"`
+	// MX are the hosts of the domain's MX records, most preferred first.
+	MX []string `json:"mx"`
+	// NullMX is true if the domain says it doesn't receive email,
+	// with a single MX record of "." from RFC 7505.
+	NullMX bool `json:"null_mx"`
+	// Addresses are the domain's A and AAAA records, which are only
+	// looked up if it has no MX records.
+	Addresses []string `json:"addresses"`
+}
+
+// AcceptsMail returns true if the domain has MX records, or no MX records
+// but an address, which RFC 5321 says is used as an implicit MX.
+func (c *DomainCheck) AcceptsMail() bool {
+	if c.NullMX {
+		return false
+	}
+	return len(c.MX) > 0 || len(c.Addresses) > 0
+}
+
+// CheckMX returns true if the domain has MX records which aren't a null MX.
+func CheckMX(ctx context.Context, r Resolver, domain string) (bool, error) {
+	mx, null, err := lookupMX(ctx, r, domain)
+	return len(mx) > 0 && !null, err
+}
+
+// CheckDomain looks up the domain's MX records, and its addresses if it has
+// none. A domain which doesn't exist has no records, rather than an error,
+// so errors are only returned when the records couldn't be looked up, like
+// on a timeout.
+func CheckDomain(ctx context.Context, r Resolver, domain string) (*DomainCheck, error) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	check := &DomainCheck{Domain: domain}
+	mx, null, err := lookupMX(ctx, r, domain)
+	if err != nil {
+		return nil, err
+	}
+	check.MX, check.NullMX = mx, null
+	if len(mx) > 0 {
+		return check, nil
+	}
+	if r == nil {
+		r = DefaultResolver
+	}
+	check.Addresses, err = r.LookupHost(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	return check, nil
+}
+
+func lookupMX(ctx context.Context, r Resolver, domain string) ([]string, bool, error) {
+	if r == nil {
+		r = DefaultResolver
+	}
+	records, err := r.LookupMX(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return nil, false, err
+	}
+	var hosts []string
+	for _, record := range records {
+		hosts = append(hosts, strings.TrimSuffix(record.Host, "."))
+	}
+	null := len(hosts) == 1 && hosts[0] == ""
+	return hosts, null, nil
+}
+
+// isNotFound returns true if the error means the domain or
+// its records don't exist.
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// DomainCache checks domains using CheckDomain, looking up each domain once.
+// It is safe for concurrent use, and is meant to last for a single run, since
+// the results aren't refreshed.
+type DomainCache struct {
+	// Resolver is used to look up the records, or DefaultResolver if nil.
+	Resolver Resolver
+
+	mu     sync.Mutex
+	checks map[string]*DomainCheck
+}
+
+// CheckDomain returns the cached check of the domain, checking it the first
+// time. Errors aren't cached, so a failed lookup is tried again.
+func (c *DomainCache) CheckDomain(ctx context.Context, domain string) (*DomainCheck, error) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	c.mu.Lock()
+	check, ok := c.checks[domain]
+	c.mu.Unlock()
+	if ok {
+		return check, nil
+	}
+	check, err := CheckDomain(ctx, c.Resolver, domain)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.checks == nil {
+		c.checks = map[string]*DomainCheck{}
+	}
+	c.checks[domain] = check
+	return check, nil
+}
+
+// FakeResolver is an in-memory Resolver for tests. Domains without
+// records or an error give the same error as a domain which doesn't exist.
+type FakeResolver struct {
+	// MX are the MX hosts of each domain.
+	MX map[string][]string
+	// Hosts are the addresses of each domain.
+	Hosts map[string][]string
+	// Errs are the errors returned for each domain, like a timeout.
+	Errs map[string]error
+	// Lookups counts the lookups made for each domain.
+	Lookups map[string]int
+
+	mu sync.Mutex
+}
+
+// LookupMX returns the domain's MX hosts, with a preference in their order.
+func (f *FakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if err := f.lookup(name); err != nil {
+		return nil, err
+	}
+	hosts, ok := f.MX[name]
+	if !ok {
+		return nil, notFound(name)
+	}
+	var records []*net.MX
+	for i, host := range hosts {
+		records = append(records, &net.MX{Host: host + ".", Pref: uint16(10 * (i + 1))})
+	}
+	return records, nil
+}
+
+// LookupHost returns the domain's addresses.
+func (f *FakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if err := f.lookup(host); err != nil {
+		return nil, err
+	}
+	addrs, ok := f.Hosts[host]
+	if !ok {
+		return nil, notFound(host)
+	}
+	return addrs, nil
+}
+
+func (f *FakeResolver) lookup(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Lookups == nil {
+		f.Lookups = map[string]int{}
+	}
+	f.Lookups[name]++
+	return f.Errs[name]
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
@@ -0,0 +1,95 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func testResolver() *FakeResolver {
+	return &FakeResolver{
+		MX: map[string][]string{
+			"close.io":     {"aspmx.l.google.com", "alt1.aspmx.l.google.com"},
+			"nomail.test":  {""},
+			"apexonly.com": nil,
+		},
+		Hosts: map[string][]string{
+			"apexonly.com": {"192.0.2.1"},
+			"nomail.test":  {"192.0.2.2"},
+		},
+		Errs: map[string]error{
+			"timeout.com": errors.New("i/o timeout"),
+		},
+	}
+}
+
+func TestCheckMX(t *testing.T) {
+	r := testResolver()
+	tests := []struct {
+		domain string
+		mx     bool
+	}{
+		{"close.io", true},
+		{"nomail.test", false},
+		{"apexonly.com", false},
+		{"missing.com", false},
+	}
+	for _, test := range tests {
+		mx, err := CheckMX(context.Background(), r, test.domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mx != test.mx {
+			t.Errorf("expected %s to have MX records: %v", test.domain, test.mx)
+		}
+	}
+	if _, err := CheckMX(context.Background(), r, "timeout.com"); err == nil {
+		t.Error("expected the lookup error")
+	}
+}
+
+func TestCheckDomain(t *testing.T) {
+	r := testResolver()
+	tests := []struct {
+		domain      string
+		acceptsMail bool
+	}{
+		{"Close.io.", true},
+		{"nomail.test", false},
+		{"apexonly.com", true},
+		{"missing.com", false},
+	}
+	for _, test := range tests {
+		check, err := CheckDomain(context.Background(), r, test.domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if check.AcceptsMail() != test.acceptsMail {
+			t.Errorf("expected %s accepting mail to be %v, got: %+v", test.domain, test.acceptsMail, check)
+		}
+	}
+	check, _ := CheckDomain(context.Background(), r, "close.io")
+	if len(check.MX) != 2 || check.MX[0] != "aspmx.l.google.com" || check.Addresses != nil {
+		t.Errorf("expected only the MX hosts, got: %+v", check)
+	}
+	if _, err := CheckDomain(context.Background(), r, "timeout.com"); err == nil {
+		t.Error("expected the lookup error")
+	}
+}
+
+func TestDomainCache(t *testing.T) {
+	r := testResolver()
+	cache := &DomainCache{Resolver: r}
+	for i := 0; i < 3; i++ {
+		if _, err := cache.CheckDomain(context.Background(), "apexonly.com"); err != nil {
+			t.Fatal(err)
+		}
+		cache.CheckDomain(context.Background(), "timeout.com")
+	}
+	if n := r.Lookups["apexonly.com"]; n != 2 {
+		t.Error("expected one MX and one host lookup, got:", n)
+	}
+	if n := r.Lookups["timeout.com"]; n != 3 {
+		t.Error("expected errors not to be cached, got lookups:", n)
+	}
+}
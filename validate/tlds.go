@@ -0,0 +1,18 @@
+package validate
+
+import "golang.org/x/net/publicsuffix"
+
+// TLDs are top-level domains, in their ASCII form, accepted in addition to
+// those of the ICANN section of the public suffix list, like a domain only
+// used internally. Add to it to allow others.
+var TLDs = map[string]bool{}
+
+// KnownTLD returns true if the ASCII top-level domain is delegated by ICANN,
+// according to the public suffix list, or is in TLDs.
+func KnownTLD(tld string) bool {
+	if TLDs[tld] {
+		return true
+	}
+	_, icann := publicsuffix.PublicSuffix(tld)
+	return icann
+}
@@ -0,0 +1,94 @@
+package validate
+
+import "strings"
+
+// CommonDomains are the email domains typos are suggested for.
+var CommonDomains = []string{
+	"gmail.com", "googlemail.com", "yahoo.com", "yahoo.co.uk", "yahoo.fr", "hotmail.com",
+	"hotmail.co.uk", "hotmail.fr", "outlook.com", "live.com", "msn.com", "icloud.com",
+	"me.com", "mac.com", "aol.com", "protonmail.com", "proton.me", "gmx.com", "gmx.de",
+	"web.de", "yandex.ru", "mail.ru", "comcast.net", "verizon.net", "att.net", "orange.fr",
+	"free.fr", "qq.com", "163.com",
+}
+
+// commonTLDs are the top-level domains typos are suggested for,
+// when the domain isn't close to one of CommonDomains.
+var commonTLDs = []string{"com", "net", "org", "edu", "gov", "io", "co", "de", "fr", "uk"}
+
+// maxTypoDistance is the most edits a typo may have.
+const maxTypoDistance = 2
+
+// suggestDomain returns the common domain the given one is probably
+// a typo of, or an empty string if there isn't one.
+func suggestDomain(domain string) string {
+	best, bestDistance := "", maxTypoDistance+1
+	for _, common := range CommonDomains {
+		if domain == common {
+			return ""
+		}
+		// short domains are too close to other domains to guess
+		if len(common) < 8 {
+			continue
+		}
+		d := distance(domain, common)
+		if len(common) < 10 && d > 1 {
+			continue
+		}
+		if d < bestDistance {
+			best, bestDistance = common, d
+		}
+	}
+	if best != "" {
+		return best
+	}
+	dot := strings.LastIndexByte(domain, '.')
+	tld := domain[dot+1:]
+	// .cm and .om are known, but much more often typos of .com
+	if KnownTLD(tld) && tld != "cm" && tld != "om" {
+		return ""
+	}
+	for _, common := range commonTLDs {
+		if distance(tld, common) == 1 {
+			return domain[:dot+1] + common
+		}
+	}
+	return ""
+}
+
+// distance returns the optimal string alignment distance between a and b:
+// the number of insertions, deletions, substitutions and transpositions of
+// adjacent characters needed to turn one into the other.
+func distance(a, b string) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := min3(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && rows[i-2][j-2]+1 < d {
+				d = rows[i-2][j-2] + 1
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,227 @@
+// Package validate checks email addresses offline, to avoid spending
+// verifier credits on addresses which clearly can't be delivered to.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/picatz/hunter/internal/idna"
+)
+
+// Reasons an address is invalid, wrapped with more details in Result.Err.
+var (
+	// ErrSyntax means the address isn't allowed by RFC 5322, or RFC 6531
+	// for internationalized addresses.
+	ErrSyntax = errors.New("invalid email address syntax")
+	// ErrDomain means the domain isn't a valid internet domain name,
+	// like "localhost" or "a..com".
+	ErrDomain = errors.New("invalid email address domain")
+	// ErrUnknownTLD means the domain's top-level domain isn't known, see KnownTLD.
+	ErrUnknownTLD = errors.New("unknown top-level domain")
+)
+
+// Limits on the length of addresses from RFC 5321, in bytes.
+const (
+	maxLocalLength   = 64
+	maxDomainLength  = 253
+	maxAddressLength = 254
+	maxLabelLength   = 63
+)
+
+// Result is the outcome of checking an email address.
+type Result struct {
+	Address string `json:"address"`
+	// Local is the part of the address before the "@", and Domain is the
+	// part after it, lowercased and in its ASCII form.
+	Local  string `json:"local"`
+	Domain string `json:"domain"`
+	// Err is why the address is invalid, wrapping ErrSyntax, ErrDomain or
+	// ErrUnknownTLD, or nil if it may be valid.
+	Err error `json:"-"`
+	// Class is whether the domain is a webmail, disposable or corporate
+	// domain, using the Domains list.
+	Class Class `json:"class,omitempty"`
+	// Role is true for addresses of a role rather than a person, like info@.
+	Role bool `json:"role"`
+	// Suggestion is the address the given one is probably a typo
+	// of, like "john@gmail.com" for "john@gmial.com".
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Valid returns true if the address may be valid, and
+// should be verified using the API to know for sure.
+func (r *Result) Valid() bool {
+	return r.Err == nil
+}
+
+// Email checks the syntax of the email address, that its domain is a valid
+// internet domain name with a known top-level domain, and whether it is a
+// role address, a webmail or disposable address, or a typo of a common address.
+func Email(address string) *Result {
+	r := &Result{Address: address}
+	r.Local, r.Domain, r.Err = split(strings.TrimSpace(address))
+	if r.Domain == "" {
+		return r
+	}
+	r.Class = Classify(r.Domain)
+	r.Role = IsRole(r.Local)
+	if suggestion := suggestDomain(r.Domain); suggestion != "" {
+		r.Suggestion = r.Local + "@" + suggestion
+	}
+	return r
+}
+
+// split returns the checked local part and domain of the address.
+func split(address string) (string, string, error) {
+	if !utf8.ValidString(address) {
+		return "", "", fmt.Errorf("%w: not UTF-8", ErrSyntax)
+	}
+	at := strings.LastIndexByte(address, '@')
+	if at < 0 {
+		return "", "", fmt.Errorf("%w: missing @", ErrSyntax)
+	}
+	local, domain := address[:at], address[at+1:]
+	if err := checkLocal(local); err != nil {
+		return "", "", err
+	}
+	if strings.HasPrefix(domain, "[") {
+		return "", "", fmt.Errorf("%w: address literals like %s aren't used for email", ErrDomain, domain)
+	}
+	ascii, err := idna.ToASCII(domain)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s", ErrDomain, domain)
+	}
+	// the parts are returned with an unknown top-level
+	// domain, so a typo of it can be suggested
+	if err := checkDomain(ascii); err != nil {
+		if errors.Is(err, ErrUnknownTLD) {
+			return local, ascii, err
+		}
+		return "", "", err
+	}
+	if len(local)+1+len(ascii) > maxAddressLength {
+		return "", "", fmt.Errorf("%w: longer than %d bytes", ErrSyntax, maxAddressLength)
+	}
+	return local, ascii, nil
+}
+
+// checkLocal checks the local part is a dot-atom or quoted string.
+func checkLocal(local string) error {
+	switch {
+	case local == "":
+		return fmt.Errorf("%w: missing the part before the @", ErrSyntax)
+	case len(local) > maxLocalLength:
+		return fmt.Errorf("%w: the part before the @ is longer than %d bytes", ErrSyntax, maxLocalLength)
+	case len(local) >= 2 && local[0] == '"' && local[len(local)-1] == '"':
+		return checkQuoted(local[1 : len(local)-1])
+	}
+	for i, atom := range strings.Split(local, ".") {
+		if atom == "" {
+			if i == 0 {
+				return fmt.Errorf("%w: starts with a period", ErrSyntax)
+			}
+			return fmt.Errorf("%w: empty part between periods", ErrSyntax)
+		}
+		for _, r := range atom {
+			if !isAtext(r) {
+				return fmt.Errorf("%w: %q isn't allowed before the @ unless quoted", ErrSyntax, r)
+			}
+		}
+	}
+	return nil
+}
+
+// checkQuoted checks the content of a quoted local part.
+func checkQuoted(content string) error {
+	escaped := false
+	for _, r := range content {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			return fmt.Errorf("%w: unescaped quote", ErrSyntax)
+		case r < ' ' || r == 0x7f:
+			return fmt.Errorf("%w: control character", ErrSyntax)
+		}
+	}
+	if escaped {
+		return fmt.Errorf("%w: unfinished escape", ErrSyntax)
+	}
+	return nil
+}
+
+// isAtext returns true for the characters allowed in an unquoted local part,
+// which are the atext of RFC 5322 and any non-ASCII character from RFC 6531.
+func isAtext(r rune) bool {
+	switch {
+	case r >= utf8.RuneSelf:
+		return r != utf8.RuneError
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
+
+// checkDomain checks the ASCII domain has at least two valid labels,
+// and a known top-level domain.
+func checkDomain(domain string) error {
+	if domain == "" {
+		return fmt.Errorf("%w: missing the domain after the @", ErrSyntax)
+	}
+	if len(domain) > maxDomainLength {
+		return fmt.Errorf("%w: longer than %d bytes", ErrDomain, maxDomainLength)
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("%w: %s has no top-level domain", ErrDomain, domain)
+	}
+	for _, label := range labels {
+		switch {
+		case label == "":
+			return fmt.Errorf("%w: %s has an empty label", ErrDomain, domain)
+		case len(label) > maxLabelLength:
+			return fmt.Errorf("%w: %s has a label longer than %d bytes", ErrDomain, domain, maxLabelLength)
+		case label[0] == '-' || label[len(label)-1] == '-':
+			return fmt.Errorf("%w: %s has a label starting or ending with a hyphen", ErrDomain, domain)
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+				return fmt.Errorf("%w: %q isn't allowed in %s", ErrDomain, c, domain)
+			}
+		}
+	}
+	if tld := labels[len(labels)-1]; !KnownTLD(tld) {
+		return fmt.Errorf("%w: .%s", ErrUnknownTLD, tld)
+	}
+	return nil
+}
+
+// roles are the local parts of common role addresses.
+var roles = map[string]bool{
+	"abuse": true, "accounting": true, "accounts": true, "admin": true, "administrator": true,
+	"billing": true, "booking": true, "bookings": true, "careers": true, "contact": true,
+	"customerservice": true, "enquiries": true, "enquiry": true, "feedback": true, "finance": true,
+	"hello": true, "help": true, "helpdesk": true, "hostmaster": true, "hr": true, "info": true,
+	"inquiries": true, "inquiry": true, "invoices": true, "it": true, "jobs": true, "legal": true,
+	"mail": true, "marketing": true, "media": true, "news": true, "newsletter": true,
+	"no-reply": true, "noreply": true, "office": true, "orders": true, "postmaster": true,
+	"press": true, "privacy": true, "recruiting": true, "reservations": true, "root": true,
+	"sales": true, "security": true, "service": true, "support": true, "team": true,
+	"webmaster": true,
+}
+
+// IsRole returns true if the local part is of a role address, like
+// "info" or "sales", rather than a person's address.
+func IsRole(local string) bool {
+	local = strings.ToLower(local)
+	if i := strings.IndexByte(local, '+'); i > 0 {
+		local = local[:i]
+	}
+	return roles[local]
+}
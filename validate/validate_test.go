@@ -0,0 +1,81 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEmail(t *testing.T) {
+	tests := []struct {
+		address string
+		err     error
+	}{
+		{"steli@close.io", nil},
+		{"First.Last+tag@Example.COM", nil},
+		{`"john doe"@example.com`, nil},
+		{`"a@b"@example.com`, nil},
+		{"josé@bücher.de", nil},
+		{"user@пример.рф", nil},
+		{"o'brien@example.ie", nil},
+		{"anna@breizh.bzh", nil},
+		{"coach@club.rugby", nil},
+		{"john@corp.internal", ErrUnknownTLD},
+		{"john@@acme.com", ErrSyntax},
+		{"john@acme", ErrDomain},
+		{"foo@localhost", ErrDomain},
+		{"john.acme.com", ErrSyntax},
+		{"@acme.com", ErrSyntax},
+		{"john@", ErrSyntax},
+		{".john@acme.com", ErrSyntax},
+		{"john..doe@acme.com", ErrSyntax},
+		{"john doe@acme.com", ErrSyntax},
+		{`"john"doe"@acme.com`, ErrSyntax},
+		{"john@acme..com", ErrDomain},
+		{"john@-acme.com", ErrDomain},
+		{"john@acme_corp.com", ErrDomain},
+		{"john@[192.168.0.1]", ErrDomain},
+		{"john@acme.notatld", ErrUnknownTLD},
+		{"john@acme.con", ErrUnknownTLD},
+	}
+	for _, test := range tests {
+		r := Email(test.address)
+		if !errors.Is(r.Err, test.err) || (test.err == nil) != r.Valid() {
+			t.Errorf("expected %q to give %v, got: %v", test.address, test.err, r.Err)
+		}
+	}
+	if r := Email("user@Bücher.de"); r.Domain != "xn--bcher-kva.de" {
+		t.Error("expected the ASCII domain, got:", r.Domain)
+	}
+	TLDs["internal"] = true
+	defer delete(TLDs, "internal")
+	if r := Email("john@corp.internal"); r.Err != nil {
+		t.Error("expected a TLD added to TLDs to be known, got:", r.Err)
+	}
+}
+
+func TestEmail_roleAndSuggestion(t *testing.T) {
+	tests := []struct {
+		address    string
+		role       bool
+		suggestion string
+	}{
+		{"steli@close.io", false, ""},
+		{"Info@close.io", true, ""},
+		{"sales+eu@close.io", true, ""},
+		{"john@gmail.com", false, ""},
+		{"john@gmial.com", false, "john@gmail.com"},
+		{"john@hotmial.com", false, "john@hotmail.com"},
+		{"john@yahooo.com", false, "john@yahoo.com"},
+		{"john@gmail.co", false, "john@gmail.com"},
+		{"john@acme.con", false, "john@acme.com"},
+		{"john@acme.cmo", false, "john@acme.com"},
+		{"john@acme.co", false, ""},
+		{"john@msa.com", false, ""},
+	}
+	for _, test := range tests {
+		r := Email(test.address)
+		if r.Role != test.role || r.Suggestion != test.suggestion {
+			t.Errorf("expected %q to have role %v and suggestion %q, got: %v and %q", test.address, test.role, test.suggestion, r.Role, r.Suggestion)
+		}
+	}
+}
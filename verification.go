@@ -0,0 +1,40 @@
+package hunter
+
+// VerificationStatus is the status of an email address verification.
+type VerificationStatus string
+
+// Verification statuses returned by the API, also accepted by the
+// "verification_status" domain search parameter where noted.
+const (
+	// VerificationStatusValid means the email address is deliverable.
+	// Accepted by the domain search.
+	VerificationStatusValid VerificationStatus = "valid"
+	// VerificationStatusInvalid means the email address is not deliverable.
+	VerificationStatusInvalid VerificationStatus = "invalid"
+	// VerificationStatusAcceptAll means the mail server accepts all email
+	// addresses, so deliverability can't be confirmed.
+	// Accepted by the domain search.
+	VerificationStatusAcceptAll VerificationStatus = "accept_all"
+	// VerificationStatusWebmail means the email address is from a webmail
+	// provider like Gmail, which are not verified.
+	VerificationStatusWebmail VerificationStatus = "webmail"
+	// VerificationStatusDisposable means the email address is from a
+	// disposable email service.
+	VerificationStatusDisposable VerificationStatus = "disposable"
+	// VerificationStatusUnknown means the email address couldn't be verified.
+	// Accepted by the domain search.
+	VerificationStatusUnknown VerificationStatus = "unknown"
+)
+
+// IsSafeToSend returns true if the status means an email sent to the
+// address is expected to be delivered.
+func (s VerificationStatus) IsSafeToSend() bool {
+	return s == VerificationStatusValid
+}
+
+// Verification is the latest verification of an email address found
+// by a domain search. It is zero if the address was never verified.
+type Verification struct {
+	Date   Date               `json:"date"`
+	Status VerificationStatus `json:"status"`
+}
@@ -0,0 +1,63 @@
+package hunter
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEmailVerifierResult_IsSafeToSend(t *testing.T) {
+	tests := []struct {
+		input string
+		safe  bool
+	}{
+		{`{"data":{"status":"valid","result":"deliverable"}}`, true},
+		{`{"data":{"status":"accept_all","result":"risky"}}`, false},
+		{`{"data":{"status":"webmail","result":"risky"}}`, false},
+		{`{"data":{"status":"invalid","result":"undeliverable"}}`, false},
+		{`{"data":{"result":"deliverable"}}`, true},
+		{`{"data":{"result":"risky"}}`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			var result EmailVerifierResult
+			if err := json.Unmarshal([]byte(test.input), &result); err != nil {
+				t.Fatal(err)
+			}
+			if result.IsSafeToSend() != test.safe {
+				t.Errorf("expected IsSafeToSend to be %v", test.safe)
+			}
+		})
+	}
+}
+
+func TestDomainEmail_Verification(t *testing.T) {
+	tests := []struct {
+		input  string
+		status VerificationStatus
+		date   string
+		safe   bool
+	}{
+		{`{"verification":{"date":"2019-12-06","status":"valid"}}`, VerificationStatusValid, "2019-12-06", true},
+		{`{"verification":{"date":null,"status":null}}`, "", "", false},
+		{`{"verification":null}`, "", "", false},
+		{`{}`, "", "", false},
+		{`{"verification":{"date":"2020-01-02","status":"accept_all"}}`, VerificationStatusAcceptAll, "2020-01-02", false},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			var email DomainEmail
+			if err := json.Unmarshal([]byte(test.input), &email); err != nil {
+				t.Fatal(err)
+			}
+			if email.Verification.Status != test.status {
+				t.Errorf("got status %q, want %q", email.Verification.Status, test.status)
+			}
+			if test.date != "" && email.Verification.Date.String() != test.date {
+				t.Errorf("got date %v, want %v", email.Verification.Date, test.date)
+			}
+			if email.IsSafeToSend() != test.safe {
+				t.Errorf("expected IsSafeToSend to be %v", test.safe)
+			}
+		})
+	}
+}